@@ -0,0 +1,246 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// checkpoint inspects and verifies signed checkpoints.
+//
+// It reads a checkpoint from the named file, or from stdin if no file or "-"
+// is given, and prints its contents and signatures:
+//
+//	checkpoint [-log_vkey <vkey>] [-vkey <vkey>]... [-policy <file>] [-origin <origin>] [file]
+//
+// Signatures from the log key and any additional keys are verified, and the
+// witness policy, if given, is checked for satisfaction. The exit code
+// describes the first check to fail:
+//
+//	0 all checks passed
+//	1 the input, keys or policy could not be read
+//	2 invalid command-line usage
+//	3 the checkpoint is malformed
+//	4 the origin is not the one expected
+//	5 a signature from a known key is invalid
+//	6 there is no valid signature from the log key
+//	7 the witness policy is not satisfied
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/transparency-dev/formats/cmd/internal/cli"
+	"github.com/transparency-dev/formats/cmd/internal/keys"
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
+	"github.com/transparency-dev/formats/witness"
+	"golang.org/x/mod/sumdb/note"
+)
+
+const (
+	exitMalformed          = 3
+	exitOriginMismatch     = 4
+	exitInvalidSignature   = 5
+	exitNoLogSignature     = 6
+	exitPolicyNotSatisfied = 7
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// knownKey is a verifier along with the algorithm of the key it was built from.
+type knownKey struct {
+	v     note.Verifier
+	alg   keys.Alg
+	isLog bool
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	return cli.Exit(inspect(args, stdin, stdout, stderr), stderr)
+}
+
+// inspect prints the checkpoint and the results of checking it, returning an
+// error from cli.Fail for the first check to fail.
+func inspect(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("checkpoint", flag.ContinueOnError)
+	logVKey := fs.String("log_vkey", "", "Verifier key of the log. If set, the checkpoint must carry a valid signature from this key.")
+	origin := fs.String("origin", "", "Expected origin of the checkpoint. If unset, any origin is accepted.")
+	policyFile := fs.String("policy", "", "Path to a witness policy file which the checkpoint's cosignatures must satisfy.")
+	var vkeys keys.VKeysFlag
+	fs.Var(&vkeys, "vkey", "Verifier key of an additional signer, such as a witness. May be repeated.")
+	if err := cli.ParseFlags(fs, args, stderr, "checkpoint file"); err != nil {
+		return err
+	}
+
+	raw, err := readInput(fs.Arg(0), stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+
+	known := make(map[string]knownKey)
+	addKey := func(vkey string, isLog bool) error {
+		vs, err := keys.Verifiers(vkey)
+		if err != nil {
+			return fmt.Errorf("invalid vkey %q: %v", vkey, err)
+		}
+		alg, err := keys.KeyAlg(vkey)
+		if err != nil {
+			return fmt.Errorf("invalid vkey %q: %v", vkey, err)
+		}
+		for i, v := range vs {
+			if i > 0 {
+				// The additional verifier is for cosignature/v1 signatures.
+				alg = keys.Ed25519CosignatureV1
+			}
+			id := keyID(v.Name(), v.KeyHash())
+			k := known[id]
+			k.v, k.alg, k.isLog = v, alg, k.isLog || isLog
+			known[id] = k
+		}
+		return nil
+	}
+	if *logVKey != "" {
+		if err := addKey(*logVKey, true); err != nil {
+			return err
+		}
+	}
+	for _, vkey := range vkeys {
+		if err := addKey(vkey, false); err != nil {
+			return err
+		}
+	}
+	var policy witness.Group
+	if *policyFile != "" {
+		var err error
+		if policy, err = witness.ParsePolicyFS(os.DirFS(filepath.Dir(*policyFile)), filepath.Base(*policyFile)); err != nil {
+			return fmt.Errorf("invalid policy %s: %v", *policyFile, err)
+		}
+	}
+
+	// Open the note without verifying any signatures, so that they can be
+	// checked and reported individually below.
+	var uErr *note.UnverifiedNoteError
+	if _, err := note.Open(raw, note.VerifierList()); !errors.As(err, &uErr) {
+		return cli.Fail(exitMalformed, "malformed note: %v", err)
+	}
+	n := uErr.Note
+	var cp log.Checkpoint
+	rest, err := cp.Unmarshal([]byte(n.Text))
+	if err != nil {
+		return cli.Fail(exitMalformed, "malformed checkpoint: %v", err)
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Origin:\t%s\n", cp.Origin)
+	fmt.Fprintf(w, "Size:\t%d\n", cp.Size)
+	fmt.Fprintf(w, "Hash:\t%s\n", base64.StdEncoding.EncodeToString(cp.Hash))
+	fmt.Fprintf(w, "Log ID:\t%s\n", log.ID(cp.Origin))
+	for _, l := range strings.Split(strings.TrimSuffix(string(rest), "\n"), "\n") {
+		if l != "" {
+			fmt.Fprintf(w, "Extension:\t%s\n", l)
+		}
+	}
+
+	var invalid []string
+	logSigned := false
+	for _, s := range n.UnverifiedSigs {
+		alg, ts, status := "unknown", "-", "unknown key"
+		if k, ok := known[keyID(s.Name, s.Hash)]; ok {
+			alg = k.alg.String()
+			if t, ok := sigTimestamp(k.alg, s); ok {
+				ts = t.UTC().Format(time.RFC3339)
+			}
+			status = "invalid"
+			if verify(k.v, n.Text, s) {
+				status = "verified"
+				logSigned = logSigned || k.isLog
+			} else {
+				invalid = append(invalid, keyID(s.Name, s.Hash))
+			}
+			if k.isLog {
+				status += " (log)"
+			}
+		}
+		fmt.Fprintf(w, "Signature:\t%s\t%s\t%s\t%s\n", keyID(s.Name, s.Hash), alg, ts, status)
+	}
+	satisfied := true
+	if *policyFile != "" {
+		satisfied = policy.Satisfied(raw)
+		if satisfied {
+			fmt.Fprintln(w, "Policy:\tsatisfied")
+		} else {
+			fmt.Fprintln(w, "Policy:\tnot satisfied")
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %v", err)
+	}
+
+	switch {
+	case *origin != "" && cp.Origin != *origin:
+		return cli.Fail(exitOriginMismatch, "%v", &log.OriginMismatchError{Got: cp.Origin, Want: *origin})
+	case len(invalid) > 0:
+		return cli.Fail(exitInvalidSignature, "invalid signatures from %s", strings.Join(invalid, ", "))
+	case *logVKey != "" && !logSigned:
+		return cli.Fail(exitNoLogSignature, "%v", log.ErrNoLogSignature)
+	case !satisfied:
+		return cli.Fail(exitPolicyNotSatisfied, "witness policy not satisfied")
+	}
+	return nil
+}
+
+// readInput returns the contents of the named file, or of stdin if the name
+// is empty or "-".
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "" || name == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// keyID returns the <name>+<hash> form used to identify keys.
+func keyID(name string, hash uint32) string {
+	return fmt.Sprintf("%s+%08x", name, hash)
+}
+
+// sigTimestamp returns the timestamp embedded in s, if signatures of the
+// given algorithm have one.
+func sigTimestamp(alg keys.Alg, s note.Signature) (time.Time, bool) {
+	var t time.Time
+	var err error
+	switch alg {
+	case keys.Ed25519CosignatureV1, keys.MLDSA44:
+		t, err = f_note.CoSigV1Timestamp(s)
+	case keys.RFC6962STH:
+		t, err = f_note.RFC6962STHTimestamp(s)
+	default:
+		return time.Time{}, false
+	}
+	return t, err == nil
+}
+
+// verify reports whether s is a valid signature over text from v.
+func verify(v note.Verifier, text string, s note.Signature) bool {
+	sig, err := base64.StdEncoding.DecodeString(s.Base64)
+	if err != nil || len(sig) < 4 {
+		return false
+	}
+	return v.Verify([]byte(text), sig[4:])
+}
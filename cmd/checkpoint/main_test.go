@@ -0,0 +1,186 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/formats/cmd/internal/cli"
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+)
+
+func TestRun(t *testing.T) {
+	const origin = "example.com/log"
+	logSKey, logVKey, err := note.GenerateKey(rand.Reader, origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witSKey, witVKey, err := note.GenerateKey(rand.Reader, "example.com/witness")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSKey, _, err := note.GenerateKey(rand.Reader, "example.com/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logSigner, err := note.NewSigner(logSKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witSigner, err := f_note.NewSignerForCosignatureV1(witSKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSigner, err := note.NewSigner(otherSKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cp := log.Checkpoint{Origin: origin, Size: 42, Hash: make([]byte, 32)}
+	sign := func(signers ...note.Signer) []byte {
+		t.Helper()
+		b, err := note.Sign(&note.Note{Text: string(cp.Marshal())}, signers...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	cosigned := sign(logSigner, witSigner)
+	// Corrupt the final byte of the witness cosignature.
+	lines := strings.Split(strings.TrimSuffix(string(cosigned), "\n"), "\n")
+	last := strings.Fields(lines[len(lines)-1])
+	sig, err := base64.StdEncoding.DecodeString(last[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[len(sig)-1] ^= 1
+	lines[len(lines)-1] = strings.Join([]string{last[0], last[1], base64.StdEncoding.EncodeToString(sig)}, " ")
+	forged := []byte(strings.Join(lines, "\n") + "\n")
+
+	dir := t.TempDir()
+	writeFile := func(name string, b []byte) string {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	cosignedFile := writeFile("cosigned", cosigned)
+	policy := writeFile("policy", []byte("witness w1 "+witVKey+" https://example.com/witness\nquorum w1\n"))
+
+	for _, test := range []struct {
+		desc       string
+		args       []string
+		stdin      []byte
+		want       int
+		wantStdout []string
+	}{
+		{
+			desc:       "inspect",
+			args:       []string{cosignedFile},
+			want:       cli.ExitOK,
+			wantStdout: []string{"Origin:     example.com/log", "Size:       42", "Log ID:     " + log.ID(origin), "unknown key"},
+		},
+		{
+			desc:       "stdin",
+			stdin:      cosigned,
+			want:       cli.ExitOK,
+			wantStdout: []string{"Origin:     example.com/log"},
+		},
+		{
+			desc:       "verify",
+			args:       []string{"-log_vkey", logVKey, "-vkey", witVKey, "-origin", origin, cosignedFile},
+			want:       cli.ExitOK,
+			wantStdout: []string{"verified (log)", "Ed25519 cosignature/v1"},
+		},
+		{
+			desc:       "policy satisfied",
+			args:       []string{"-log_vkey", logVKey, "-policy", policy, cosignedFile},
+			want:       cli.ExitOK,
+			wantStdout: []string{"Policy:     satisfied"},
+		},
+		{
+			desc: "usage",
+			args: []string{"a", "b"},
+			want: cli.ExitUsage,
+		},
+		{
+			desc: "missing file",
+			args: []string{filepath.Join(dir, "missing")},
+			want: cli.ExitError,
+		},
+		{
+			desc: "invalid vkey",
+			args: []string{"-vkey", "bananas", cosignedFile},
+			want: cli.ExitError,
+		},
+		{
+			desc:  "malformed note",
+			stdin: cp.Marshal(),
+			want:  exitMalformed,
+		},
+		{
+			desc:  "malformed checkpoint",
+			stdin: []byte("not a checkpoint\n\n— example.com/log AAAAAAAA\n"),
+			want:  exitMalformed,
+		},
+		{
+			desc: "wrong origin",
+			args: []string{"-origin", "example.com/other", cosignedFile},
+			want: exitOriginMismatch,
+		},
+		{
+			desc:       "invalid signature",
+			args:       []string{"-vkey", witVKey},
+			stdin:      forged,
+			want:       exitInvalidSignature,
+			wantStdout: []string{"  invalid"},
+		},
+		{
+			desc:  "no log signature",
+			args:  []string{"-log_vkey", logVKey},
+			stdin: sign(witSigner),
+			want:  exitNoLogSignature,
+		},
+		{
+			desc:       "policy not satisfied",
+			args:       []string{"-log_vkey", logVKey, "-policy", policy},
+			stdin:      sign(logSigner, otherSigner),
+			want:       exitPolicyNotSatisfied,
+			wantStdout: []string{"Policy:     not satisfied"},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(test.args, bytes.NewReader(test.stdin), &stdout, &stderr); got != test.want {
+				t.Fatalf("run() = %d, want %d\nstdout:\n%s\nstderr:\n%s", got, test.want, stdout.String(), stderr.String())
+			}
+			for _, want := range test.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout does not contain %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli runs the subcommands of the command-line tools, and maps the
+// errors they return to exit codes.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// Exit codes shared by all of the command-line tools. Tools define their own
+// codes for other outcomes, starting after ExitUsage.
+const (
+	ExitOK = iota
+	ExitError
+	ExitUsage
+)
+
+// Command is a subcommand, which is passed the arguments following its name.
+type Command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+// codeError associates an error with the exit code it should produce.
+type codeError struct {
+	code int
+	err  error
+}
+
+func (e *codeError) Error() string { return e.err.Error() }
+
+func (e *codeError) Unwrap() error { return e.err }
+
+// Fail returns an error which results in the given exit code.
+func Fail(code int, format string, args ...any) error {
+	return &codeError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// Run runs the command named by the first of args, and returns the exit code
+// for its result. Errors are written to stderr, and usage is written if no
+// known command is named. The result of the command is mapped to an exit
+// code by Exit.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer, usage string, cmds map[string]Command) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return ExitUsage
+	}
+	cmd, ok := cmds[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n%s", args[0], usage)
+		return ExitUsage
+	}
+	return Exit(cmd(args[1:], stdin, stdout, stderr), stderr)
+}
+
+// Exit returns the exit code for err, the result of a command, writing it to
+// stderr if it is not nil. Errors returned by Fail produce the exit code
+// given to it, flag.ErrHelp produces ExitUsage, and any other error produces
+// ExitError.
+func Exit(err error, stderr io.Writer) int {
+	if err == nil {
+		return ExitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitUsage
+	}
+	fmt.Fprintln(stderr, err)
+	var cErr *codeError
+	if errors.As(err, &cErr) {
+		return cErr.code
+	}
+	return ExitError
+}
+
+// ParseFlags parses args with fs, which must accept at most one positional
+// argument, described by arg in the error returned if there are more.
+func ParseFlags(fs *flag.FlagSet, args []string, stderr io.Writer, arg string) error {
+	if err := ParseArgs(fs, args, stderr); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return Fail(ExitUsage, "at most one %s may be given", arg)
+	}
+	return nil
+}
+
+// ParseArgs parses args with fs, which may accept any number of positional
+// arguments. Usage and errors are written to stderr.
+func ParseArgs(fs *flag.FlagSet, args []string, stderr io.Writer) error {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return Fail(ExitUsage, "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keys describes the note key types supported by this module, for use
+// by the command-line tools.
+package keys
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// Alg is the type byte at the start of an encoded note key.
+type Alg byte
+
+// Key algorithms understood by the note package.
+const (
+	Ed25519              Alg = 1
+	ECDSAWithSHA256      Alg = 2
+	Ed25519CosignatureV1 Alg = 4
+	RFC6962STH           Alg = 5
+	MLDSA44              Alg = 6
+)
+
+var algNames = map[Alg]string{
+	Ed25519:              "Ed25519",
+	ECDSAWithSHA256:      "ECDSA-SHA256",
+	Ed25519CosignatureV1: "Ed25519 cosignature/v1",
+	RFC6962STH:           "RFC 6962 STH",
+	MLDSA44:              "ML-DSA-44 cosignature/v1",
+}
+
+func (a Alg) String() string {
+	if n, ok := algNames[a]; ok {
+		return n
+	}
+	return fmt.Sprintf("unknown (%d)", byte(a))
+}
+
+// KeyAlg returns the algorithm of a vkey, or of an skey starting with
+// "PRIVATE+KEY+". The key material itself is not validated.
+func KeyAlg(key string) (Alg, error) {
+	key = strings.TrimPrefix(key, "PRIVATE+KEY+")
+	parts := strings.SplitN(key, "+", 3)
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("key has %d parts, expected 3", len(parts))
+	}
+	b, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || len(b) == 0 {
+		return 0, fmt.Errorf("key has invalid base64 %q", parts[2])
+	}
+	return Alg(b[0]), nil
+}
+
+// VKeysFlag is a flag.Value which collects the values of a repeated vkey flag.
+type VKeysFlag []string
+
+func (f *VKeysFlag) String() string { return strings.Join(*f, ",") }
+
+// Set appends v to the list of vkeys.
+func (f *VKeysFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// Verifiers returns the verifiers for a vkey. Plain Ed25519 keys are also used
+// for cosignature/v1 signatures, which have a different key hash, so for those
+// a cosignature/v1 verifier is returned too, as is done for witness keys in
+// policy files.
+func Verifiers(vkey string) ([]note.Verifier, error) {
+	v, err := f_note.NewVerifier(vkey)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := KeyAlg(vkey)
+	if err != nil {
+		return nil, err
+	}
+	if alg != Ed25519 {
+		return []note.Verifier{v}, nil
+	}
+	cv, err := f_note.NewVerifierForCosignatureV1(vkey)
+	if err != nil {
+		return nil, err
+	}
+	return []note.Verifier{v, cv}, nil
+}
@@ -0,0 +1,280 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// notekey generates, converts and checks note signer and verifier keys.
+//
+// Usage:
+//
+//	notekey generate -name <name> [-type ed25519|cosignature|ecdsa|mldsa44] [-skey_file <file>]
+//	notekey convert -to cosignature <vkey>
+//	notekey convert -to ecdsa -name <name> -pem <file>
+//	notekey convert -to rfc6962 -url <log URL> -pem <file>
+//	notekey hash <vkey>...
+//	notekey check -skey_file <file> -vkey <vkey>
+//
+// generate prints the new skey and vkey, one per line, unless -skey_file is
+// given, in which case the skey is written to that file and only the vkey is
+// printed. The cosignature type generates an Ed25519 key whose vkey is in
+// cosignature/v1 form.
+//
+// convert prints the vkey converted from an Ed25519 vkey, or from a PEM
+// encoded public key.
+//
+// hash prints the name, key hash and algorithm of each vkey, which may also be
+// read one per line from stdin. For Ed25519 vkeys, the key hash used by
+// cosignature/v1 signatures is printed too.
+//
+// check exits with status 3 if the skey and vkey are not a matching pair.
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/transparency-dev/formats/cmd/internal/cli"
+	"github.com/transparency-dev/formats/cmd/internal/keys"
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// exitMismatch is the exit code from check when the keys are not a pair.
+const exitMismatch = 3
+
+const usage = `Usage:
+  notekey generate -name <name> [-type ed25519|cosignature|ecdsa|mldsa44] [-skey_file <file>]
+  notekey convert -to cosignature <vkey>
+  notekey convert -to ecdsa -name <name> -pem <file>
+  notekey convert -to rfc6962 -url <log URL> -pem <file>
+  notekey hash <vkey>...
+  notekey check -skey_file <file> -vkey <vkey>
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	return cli.Run(args, stdin, stdout, stderr, usage, map[string]cli.Command{
+		"generate": generate,
+		"convert":  convert,
+		"hash":     hash,
+		"check":    check,
+	})
+}
+
+func generate(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	name := fs.String("name", "", "Name of the key, typically the log origin or witness name.")
+	keyType := fs.String("type", "ed25519", "Type of key to generate: ed25519, cosignature, ecdsa or mldsa44.")
+	skeyFile := fs.String("skey_file", "", "If set, the skey is written to this file rather than stdout.")
+	if err := cli.ParseArgs(fs, args, stderr); err != nil {
+		return err
+	}
+	if *name == "" || fs.NArg() != 0 {
+		return cli.Fail(cli.ExitUsage, "generate requires -name and no arguments")
+	}
+
+	var skey, vkey string
+	var err error
+	switch *keyType {
+	case "ed25519":
+		skey, vkey, err = note.GenerateKey(rand.Reader, *name)
+	case "cosignature":
+		skey, vkey, err = note.GenerateKey(rand.Reader, *name)
+		if err == nil {
+			vkey, err = f_note.VKeyToCosignatureV1(vkey)
+		}
+	case "ecdsa":
+		skey, vkey, err = f_note.GenerateECDSAKey(*name)
+	case "mldsa44":
+		skey, vkey, err = f_note.GenerateMLDSAKey(*name)
+	default:
+		return cli.Fail(cli.ExitUsage, "unknown key type %q", *keyType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %v", err)
+	}
+
+	if *skeyFile != "" {
+		if err := os.WriteFile(*skeyFile, []byte(skey+"\n"), 0o600); err != nil {
+			return fmt.Errorf("failed to write skey: %v", err)
+		}
+	} else {
+		fmt.Fprintln(stdout, skey)
+	}
+	fmt.Fprintln(stdout, vkey)
+	return nil
+}
+
+func convert(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	to := fs.String("to", "", "Type of vkey to produce: cosignature, ecdsa or rfc6962.")
+	name := fs.String("name", "", "Name of the key, for -to ecdsa.")
+	logURL := fs.String("url", "", "Root URL of the log, for -to rfc6962.")
+	pemFile := fs.String("pem", "", "PEM encoded public key, for -to ecdsa and -to rfc6962.")
+	if err := cli.ParseFlags(fs, args, stderr, "vkey"); err != nil {
+		return err
+	}
+
+	var vkey string
+	var err error
+	switch *to {
+	case "cosignature":
+		if fs.NArg() != 1 {
+			return cli.Fail(cli.ExitUsage, "convert -to cosignature requires one vkey")
+		}
+		vkey, err = f_note.VKeyToCosignatureV1(fs.Arg(0))
+	case "ecdsa", "rfc6962":
+		if *pemFile == "" || fs.NArg() != 0 {
+			return cli.Fail(cli.ExitUsage, "convert -to %s requires -pem and no arguments", *to)
+		}
+		var pubK any
+		if pubK, err = readPublicKey(*pemFile); err != nil {
+			return err
+		}
+		if *to == "ecdsa" {
+			vkey, err = f_note.ECDSAVerifierString(*name, pubK)
+		} else {
+			vkey, err = f_note.RFC6962VerifierString(*logURL, pubK)
+		}
+	default:
+		return cli.Fail(cli.ExitUsage, "unknown vkey type %q", *to)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to convert key: %v", err)
+	}
+	fmt.Fprintln(stdout, vkey)
+	return nil
+}
+
+// readPublicKey reads a PEM encoded PKIX public key from the named file.
+func readPublicKey(name string) (any, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %v", err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("%s does not contain a PEM encoded public key", name)
+	}
+	pubK, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %v", err)
+	}
+	return pubK, nil
+}
+
+func hash(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("hash", flag.ContinueOnError)
+	if err := cli.ParseArgs(fs, args, stderr); err != nil {
+		return err
+	}
+	vkeys := fs.Args()
+	if len(vkeys) == 0 {
+		s := bufio.NewScanner(stdin)
+		for s.Scan() {
+			if l := strings.TrimSpace(s.Text()); l != "" {
+				vkeys = append(vkeys, l)
+			}
+		}
+		if err := s.Err(); err != nil {
+			return fmt.Errorf("failed to read vkeys: %v", err)
+		}
+	}
+	for _, vkey := range vkeys {
+		v, err := f_note.NewVerifier(vkey)
+		if err != nil {
+			return fmt.Errorf("invalid vkey %q: %v", vkey, err)
+		}
+		alg, err := keys.KeyAlg(vkey)
+		if err != nil {
+			return fmt.Errorf("invalid vkey %q: %v", vkey, err)
+		}
+		fmt.Fprintf(stdout, "%s+%08x %s\n", v.Name(), v.KeyHash(), alg)
+		// Cosignatures made with an Ed25519 key carry a different key hash.
+		if alg == keys.Ed25519 {
+			cv, err := f_note.NewVerifierForCosignatureV1(vkey)
+			if err != nil {
+				return fmt.Errorf("invalid vkey %q: %v", vkey, err)
+			}
+			fmt.Fprintf(stdout, "%s+%08x %s\n", cv.Name(), cv.KeyHash(), keys.Ed25519CosignatureV1)
+		}
+	}
+	return nil
+}
+
+func check(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	skeyFile := fs.String("skey_file", "", "File containing the skey.")
+	vkey := fs.String("vkey", "", "The vkey to check against the skey.")
+	if err := cli.ParseArgs(fs, args, stderr); err != nil {
+		return err
+	}
+	if *skeyFile == "" || *vkey == "" || fs.NArg() != 0 {
+		return cli.Fail(cli.ExitUsage, "check requires -skey_file and -vkey")
+	}
+	b, err := os.ReadFile(*skeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read skey: %v", err)
+	}
+	skey := strings.TrimSpace(string(b))
+
+	v, err := f_note.NewVerifier(*vkey)
+	if err != nil {
+		return fmt.Errorf("invalid vkey: %v", err)
+	}
+	alg, err := keys.KeyAlg(*vkey)
+	if err != nil {
+		return fmt.Errorf("invalid vkey: %v", err)
+	}
+	// The signer is chosen to produce signatures of the type expected by the
+	// vkey, as an Ed25519 skey may be used for either plain or cosignature/v1
+	// signatures.
+	var s note.Signer
+	switch alg {
+	case keys.Ed25519:
+		s, err = note.NewSigner(skey)
+	case keys.ECDSAWithSHA256:
+		s, err = f_note.NewECDSASigner(skey)
+	case keys.Ed25519CosignatureV1, keys.MLDSA44:
+		s, err = f_note.NewSignerForCosignatureV1(skey)
+	default:
+		return fmt.Errorf("cannot check %s keys", alg)
+	}
+	if err != nil {
+		return cli.Fail(exitMismatch, "keys do not match: skey is not a valid %s key: %v", alg, err)
+	}
+	if s.Name() != v.Name() || s.KeyHash() != v.KeyHash() {
+		return cli.Fail(exitMismatch, "keys do not match: skey is %s+%08x but vkey is %s+%08x", s.Name(), s.KeyHash(), v.Name(), v.KeyHash())
+	}
+	// Cosignature signers only sign checkpoints, so sign a dummy one.
+	cp := log.Checkpoint{Origin: "notekey check", Size: 1, Hash: make([]byte, 32)}
+	n, err := note.Sign(&note.Note{Text: string(cp.Marshal())}, s)
+	if err != nil {
+		return fmt.Errorf("failed to sign test note: %v", err)
+	}
+	if _, err := note.Open(n, note.VerifierList(v)); err != nil {
+		return cli.Fail(exitMismatch, "keys do not match: signature from skey not verified by vkey: %v", err)
+	}
+	fmt.Fprintf(stdout, "%s+%08x %s: keys match\n", v.Name(), v.KeyHash(), alg)
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/formats/cmd/internal/cli"
+	f_note "github.com/transparency-dev/formats/note"
+)
+
+// runOK runs notekey with the provided arguments, and returns its output
+// lines.
+func runOK(t *testing.T, args ...string) []string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if got := run(args, strings.NewReader(""), &stdout, &stderr); got != cli.ExitOK {
+		t.Fatalf("notekey %s = %d, want %d: %s", strings.Join(args, " "), got, cli.ExitOK, stderr.String())
+	}
+	return strings.Split(strings.TrimSpace(stdout.String()), "\n")
+}
+
+func TestGenerateAndCheck(t *testing.T) {
+	for _, keyType := range []string{"ed25519", "cosignature", "ecdsa", "mldsa44"} {
+		t.Run(keyType, func(t *testing.T) {
+			skeyFile := filepath.Join(t.TempDir(), "skey")
+			out := runOK(t, "generate", "-name", "example.com/"+keyType, "-type", keyType, "-skey_file", skeyFile)
+			if len(out) != 1 {
+				t.Fatalf("got %d lines of output, want only the vkey: %q", len(out), out)
+			}
+			vkey := out[0]
+			if _, err := f_note.NewVerifier(vkey); err != nil {
+				t.Fatalf("generated vkey %q is invalid: %v", vkey, err)
+			}
+			runOK(t, "check", "-skey_file", skeyFile, "-vkey", vkey)
+
+			// A freshly generated key of the same type must not match.
+			other := runOK(t, "generate", "-name", "example.com/"+keyType, "-type", keyType)
+			var stdout, stderr bytes.Buffer
+			if got := run([]string{"check", "-skey_file", skeyFile, "-vkey", other[1]}, nil, &stdout, &stderr); got != exitMismatch {
+				t.Errorf("check with other vkey = %d, want %d: %s", got, exitMismatch, stderr.String())
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	out := runOK(t, "convert", "-to", "cosignature", "PeterNeumann+c74f20a3+ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW")
+	want, err := f_note.VKeyToCosignatureV1("PeterNeumann+c74f20a3+ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0] != want {
+		t.Errorf("convert -to cosignature = %q, want %q", out[0], want)
+	}
+
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemFile := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(pemFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		to   string
+		args []string
+		want func() (string, error)
+	}{
+		{
+			to:   "ecdsa",
+			args: []string{"-name", "example.com/log"},
+			want: func() (string, error) { return f_note.ECDSAVerifierString("example.com/log", &k.PublicKey) },
+		},
+		{
+			to:   "rfc6962",
+			args: []string{"-url", "https://ct.example.com/log/"},
+			want: func() (string, error) {
+				return f_note.RFC6962VerifierString("https://ct.example.com/log/", &k.PublicKey)
+			},
+		},
+	} {
+		t.Run(test.to, func(t *testing.T) {
+			want, err := test.want()
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := runOK(t, append([]string{"convert", "-to", test.to, "-pem", pemFile}, test.args...)...)
+			if out[0] != want {
+				t.Errorf("convert -to %s = %q, want %q", test.to, out[0], want)
+			}
+			if _, err := f_note.NewVerifier(out[0]); err != nil {
+				t.Errorf("converted vkey is invalid: %v", err)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("PeterNeumann+c74f20a3+ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW\n")
+	if got := run([]string{"hash"}, stdin, &stdout, &stderr); got != cli.ExitOK {
+		t.Fatalf("hash = %d, want %d: %s", got, cli.ExitOK, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), lines)
+	}
+	if got, want := lines[0], "PeterNeumann+c74f20a3 Ed25519"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !strings.HasSuffix(lines[1], " Ed25519 cosignature/v1") {
+		t.Errorf("got %q, want cosignature/v1 key hash", lines[1])
+	}
+}
+
+func TestUsage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"bananas"},
+		{"generate"},
+		{"generate", "-name", "example.com", "-type", "rsa"},
+		{"convert", "-to", "cosignature"},
+		{"convert", "-to", "ecdsa"},
+		{"check", "-vkey", "example.com+12345678+AAAA"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if got := run(args, nil, &stdout, &stderr); got != cli.ExitUsage {
+			t.Errorf("notekey %q = %d, want %d", args, got, cli.ExitUsage)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// tlogproof prints, verifies and builds https://c2sp.org/tlog-proof proofs.
+//
+// Usage:
+//
+//	tlogproof print [proof file]
+//	tlogproof verify -log_vkey <vkey> (-leaf <file> | -leaf_hash <base64>) [-origin <origin>] [-vkey <vkey>]... [-policy <file>] [-hash <alg>] [proof file]
+//	tlogproof build -log_vkey <vkey> -checkpoint <file> -tiles <dir> -index <index> [-origin <origin>] [-layout tlog-tiles|sumdb] [-extra <data>] [-leaf <file> | -leaf_hash <base64>]
+//
+// Proofs are read from the named file, or from stdin if no file or "-" is
+// given.
+//
+// verify checks the proof against the leaf, whose hash is given directly or
+// computed from its contents. The checkpoint must be signed by the log, by
+// every key given with -vkey, and must satisfy the witness policy if one is
+// given.
+//
+// build creates a proof for the entry at the given index from a local
+// directory of hash tiles, laid out as described by
+// https://c2sp.org/tlog-tiles or as served by the Go checksum database. The
+// proof is written to stdout, and if a leaf is given, it is checked before
+// being written.
+//
+// The exit code describes the first check to fail:
+//
+//	0 all checks passed
+//	1 the input, keys, policy or tiles could not be read
+//	2 invalid command-line usage
+//	3 the proof is malformed
+//	4 the checkpoint is not validly signed by the log and other keys
+//	5 the leaf is not included in the checkpoint's tree
+//	6 the witness policy is not satisfied
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/transparency-dev/formats/cmd/internal/cli"
+	"github.com/transparency-dev/formats/cmd/internal/keys"
+	"github.com/transparency-dev/formats/log"
+	"github.com/transparency-dev/formats/proof"
+	"github.com/transparency-dev/formats/witness"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+const (
+	exitOK                 = cli.ExitOK
+	exitError              = cli.ExitError
+	exitUsage              = cli.ExitUsage
+	exitMalformed          = 3
+	exitBadCheckpoint      = 4
+	exitNotIncluded        = 5
+	exitPolicyNotSatisfied = 6
+)
+
+const usage = `Usage:
+  tlogproof print [proof file]
+  tlogproof verify -log_vkey <vkey> (-leaf <file> | -leaf_hash <base64>) [-origin <origin>] [-vkey <vkey>]... [-policy <file>] [-hash <alg>] [proof file]
+  tlogproof build -log_vkey <vkey> -checkpoint <file> -tiles <dir> -index <index> [-origin <origin>] [-layout tlog-tiles|sumdb] [-extra <data>] [-leaf <file> | -leaf_hash <base64>]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	return cli.Run(args, stdin, stdout, stderr, usage, map[string]cli.Command{
+		"print":  printProof,
+		"verify": verify,
+		"build":  build,
+	})
+}
+
+// readProof reads and parses a proof from the named file, or from stdin if
+// the name is empty or "-".
+func readProof(name string, stdin io.Reader, alg log.HashAlgorithm) (*proof.TLogProof, error) {
+	var b []byte
+	var err error
+	if name == "" || name == "-" {
+		b, err = io.ReadAll(stdin)
+	} else {
+		b, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proof: %v", err)
+	}
+	var p proof.TLogProof
+	if err := p.UnmarshalWithHash(b, alg); err != nil {
+		return nil, cli.Fail(exitMalformed, "malformed proof: %v", err)
+	}
+	return &p, nil
+}
+
+func printProof(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("print", flag.ContinueOnError)
+	hashAlg := fs.String("hash", log.SHA256.String(), "Hash algorithm used by the log.")
+	if err := cli.ParseFlags(fs, args, stderr, "proof file"); err != nil {
+		return err
+	}
+	alg, err := log.ParseHashAlgorithm(*hashAlg)
+	if err != nil {
+		return cli.Fail(exitUsage, "%v", err)
+	}
+	p, err := readProof(fs.Arg(0), stdin, alg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Index:\t%d\n", p.Index)
+	fmt.Fprintf(w, "Hashes:\t%d\n", len(p.ProofHashes()))
+	if p.ExtraData != nil {
+		fmt.Fprintf(w, "Extra data:\t%s\n", formatExtra(p.ExtraData))
+	}
+	var uErr *note.UnverifiedNoteError
+	if _, err := note.Open(p.Checkpoint, note.VerifierList()); !errors.As(err, &uErr) {
+		fmt.Fprintf(w, "Checkpoint:\tmalformed note: %v\n", err)
+		return w.Flush()
+	}
+	var cp log.Checkpoint
+	if _, err := cp.Unmarshal([]byte(uErr.Note.Text)); err != nil {
+		fmt.Fprintf(w, "Checkpoint:\tmalformed checkpoint: %v\n", err)
+		return w.Flush()
+	}
+	fmt.Fprintf(w, "Origin:\t%s\n", cp.Origin)
+	fmt.Fprintf(w, "Size:\t%d\n", cp.Size)
+	fmt.Fprintf(w, "Root hash:\t%s\n", base64.StdEncoding.EncodeToString(cp.Hash))
+	for _, s := range uErr.Note.UnverifiedSigs {
+		fmt.Fprintf(w, "Signature:\t%s+%08x\n", s.Name, s.Hash)
+	}
+	return w.Flush()
+}
+
+// formatExtra returns printable extra data as a quoted string, and other data
+// as base64.
+func formatExtra(b []byte) string {
+	if utf8.Valid(b) && strings.IndexFunc(string(b), func(r rune) bool { return !unicode.IsPrint(r) }) < 0 {
+		return fmt.Sprintf("%q", b)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(b)
+}
+
+// leafFlags holds the flags which identify a leaf.
+type leafFlags struct {
+	file string
+	hash string
+}
+
+func (l *leafFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&l.file, "leaf", "", "File containing the leaf data, which is hashed with the log's hash algorithm.")
+	fs.StringVar(&l.hash, "leaf_hash", "", "Base64 encoded leaf hash.")
+}
+
+// leafHash returns the leaf hash, or nil if no leaf was given.
+func (l *leafFlags) leafHash(alg log.HashAlgorithm) ([]byte, error) {
+	switch {
+	case l.file != "" && l.hash != "":
+		return nil, cli.Fail(exitUsage, "only one of -leaf and -leaf_hash may be given")
+	case l.file != "":
+		b, err := os.ReadFile(l.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read leaf: %v", err)
+		}
+		return alg.HashLeaf(b), nil
+	case l.hash != "":
+		h, err := base64.StdEncoding.DecodeString(l.hash)
+		if err != nil || len(h) != alg.Size() {
+			return nil, cli.Fail(exitUsage, "-leaf_hash must be a base64 encoded %v hash", alg)
+		}
+		return h, nil
+	}
+	return nil, nil
+}
+
+// logVerifier returns the verifier for the log's vkey, and the origin to
+// expect, which defaults to the name of the key.
+func logVerifier(vkey, origin string) (note.Verifier, string, error) {
+	if vkey == "" {
+		return nil, "", cli.Fail(exitUsage, "-log_vkey is required")
+	}
+	vs, err := keys.Verifiers(vkey)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid log vkey %q: %v", vkey, err)
+	}
+	if origin == "" {
+		origin = vs[0].Name()
+	}
+	return vs[0], origin, nil
+}
+
+func verify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	logVKey := fs.String("log_vkey", "", "Verifier key of the log.")
+	origin := fs.String("origin", "", "Origin of the log. Defaults to the name of the log's key.")
+	policyFile := fs.String("policy", "", "Path to a witness policy file which the checkpoint's cosignatures must satisfy.")
+	hashAlg := fs.String("hash", log.SHA256.String(), "Hash algorithm used by the log.")
+	var vkeys keys.VKeysFlag
+	fs.Var(&vkeys, "vkey", "Verifier key which must have signed the checkpoint, such as a witness. May be repeated.")
+	var leaf leafFlags
+	leaf.register(fs)
+	if err := cli.ParseFlags(fs, args, stderr, "proof file"); err != nil {
+		return err
+	}
+
+	alg, err := log.ParseHashAlgorithm(*hashAlg)
+	if err != nil {
+		return cli.Fail(exitUsage, "%v", err)
+	}
+	leafHash, err := leaf.leafHash(alg)
+	if err != nil {
+		return err
+	}
+	if leafHash == nil {
+		return cli.Fail(exitUsage, "one of -leaf or -leaf_hash is required")
+	}
+	logV, wantOrigin, err := logVerifier(*logVKey, *origin)
+	if err != nil {
+		return err
+	}
+	var required [][]note.Verifier
+	var others []note.Verifier
+	for _, vkey := range vkeys {
+		vs, err := keys.Verifiers(vkey)
+		if err != nil {
+			return fmt.Errorf("invalid vkey %q: %v", vkey, err)
+		}
+		required = append(required, vs)
+		others = append(others, vs...)
+	}
+	var policy *witness.Group
+	if *policyFile != "" {
+		g, err := witness.ParsePolicyFS(os.DirFS(filepath.Dir(*policyFile)), filepath.Base(*policyFile))
+		if err != nil {
+			return fmt.Errorf("invalid policy %s: %v", *policyFile, err)
+		}
+		policy = &g
+	}
+
+	p, err := readProof(fs.Arg(0), stdin, alg)
+	if err != nil {
+		return err
+	}
+	cp, err := p.Verify(alg, leafHash, wantOrigin, logV, others...)
+	switch {
+	case errors.Is(err, log.ErrMalformedProof):
+		return cli.Fail(exitMalformed, "%v", err)
+	case errors.Is(err, log.ErrRootMismatch):
+		return cli.Fail(exitNotIncluded, "%v", err)
+	case err != nil:
+		return cli.Fail(exitBadCheckpoint, "%v", err)
+	}
+	for i, vs := range required {
+		if _, err := note.Open(p.Checkpoint, note.VerifierList(vs...)); err != nil {
+			return cli.Fail(exitBadCheckpoint, "checkpoint not signed by %s: %v", vkeys[i], err)
+		}
+	}
+	if policy != nil && !policy.Satisfied(p.Checkpoint) {
+		return cli.Fail(exitPolicyNotSatisfied, "witness policy not satisfied")
+	}
+	fmt.Fprintf(stdout, "Entry %d is included in %s at size %d\n", p.Index, cp.Origin, cp.Size)
+	return nil
+}
+
+func build(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	logVKey := fs.String("log_vkey", "", "Verifier key of the log.")
+	origin := fs.String("origin", "", "Origin of the log. Defaults to the name of the log's key.")
+	cpFile := fs.String("checkpoint", "", "File containing the signed checkpoint to prove inclusion in.")
+	tilesDir := fs.String("tiles", "", "Directory containing the log's tiles.")
+	layout := fs.String("layout", "tlog-tiles", "Layout of the tiles directory: tlog-tiles, or sumdb for the Go checksum database.")
+	index := fs.Int64("index", -1, "Index of the entry to prove.")
+	extra := fs.String("extra", "", "Extra data to include in the proof.")
+	var leaf leafFlags
+	leaf.register(fs)
+	if err := cli.ParseFlags(fs, args, stderr, "proof file"); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return cli.Fail(exitUsage, "build does not take a proof file")
+	}
+	if *cpFile == "" || *tilesDir == "" || *index < 0 {
+		return cli.Fail(exitUsage, "-checkpoint, -tiles and -index are required")
+	}
+	if *layout != "tlog-tiles" && *layout != "sumdb" {
+		return cli.Fail(exitUsage, "unknown tiles layout %q", *layout)
+	}
+	leafHash, err := leaf.leafHash(log.SHA256)
+	if err != nil {
+		return err
+	}
+	logV, wantOrigin, err := logVerifier(*logVKey, *origin)
+	if err != nil {
+		return err
+	}
+	signed, err := os.ReadFile(*cpFile)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+
+	p, err := proof.NewTLogProofFromTiles(signed, wantOrigin, logV, uint64(*index), dirTiles{dir: *tilesDir, sumdb: *layout == "sumdb"})
+	if err != nil {
+		return err
+	}
+	if *extra != "" {
+		p.ExtraData = []byte(*extra)
+	}
+	if leafHash != nil {
+		if _, err := p.Verify(log.SHA256, leafHash, wantOrigin, logV); err != nil {
+			return cli.Fail(exitNotIncluded, "%v", err)
+		}
+	}
+	_, err = stdout.Write(p.Marshal())
+	return err
+}
+
+// dirTiles is a tlog.TileReader for a local directory of tiles.
+type dirTiles struct {
+	dir string
+	// sumdb is true for the layout used by the Go checksum database, which
+	// includes the tile height in paths.
+	sumdb bool
+}
+
+func (d dirTiles) Height() int { return 8 }
+
+// ReadTiles reads the requested tiles. Logs may delete partial tiles once the
+// full tile is available, so a full tile is used in place of a missing
+// partial one.
+func (d dirTiles) ReadTiles(tiles []tlog.Tile) ([][]byte, error) {
+	data := make([][]byte, len(tiles))
+	for i, t := range tiles {
+		b, err := os.ReadFile(d.path(t))
+		if errors.Is(err, fs.ErrNotExist) && t.W < 1<<t.H {
+			full := t
+			full.W = 1 << t.H
+			if b, err = os.ReadFile(d.path(full)); err == nil {
+				if len(b) < t.W*tlog.HashSize {
+					return nil, fmt.Errorf("full tile %s too short: got %d bytes, want at least %d", full.Path(), len(b), t.W*tlog.HashSize)
+				}
+				b = b[:t.W*tlog.HashSize]
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tile: %v", err)
+		}
+		data[i] = b
+	}
+	return data, nil
+}
+
+func (d dirTiles) SaveTiles([]tlog.Tile, [][]byte) {}
+
+func (d dirTiles) path(t tlog.Tile) string {
+	p := t.Path()
+	if !d.sumdb {
+		// https://c2sp.org/tlog-tiles paths omit the tile height.
+		p = "tile/" + strings.TrimPrefix(p, "tile/8/")
+	}
+	return filepath.Join(d.dir, filepath.FromSlash(p))
+}
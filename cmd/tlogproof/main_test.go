@@ -0,0 +1,319 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+const testOrigin = "example.com/log"
+
+// testLog is a log whose tiles are written to a directory in the
+// https://c2sp.org/tlog-tiles layout.
+type testLog struct {
+	dir    string
+	hashes []tlog.Hash
+}
+
+func leafData(i int) []byte { return fmt.Appendf(nil, "leaf %d", i) }
+
+func newTestLog(t *testing.T, size int) *testLog {
+	t.Helper()
+	l := &testLog{dir: t.TempDir()}
+	for i := range size {
+		hs, err := tlog.StoredHashes(int64(i), leafData(i), l.reader())
+		if err != nil {
+			t.Fatalf("StoredHashes: %v", err)
+		}
+		l.hashes = append(l.hashes, hs...)
+	}
+	return l
+}
+
+func (l *testLog) reader() tlog.HashReader {
+	return tlog.HashReaderFunc(func(indexes []int64) ([]tlog.Hash, error) {
+		hs := make([]tlog.Hash, len(indexes))
+		for i, idx := range indexes {
+			hs[i] = l.hashes[idx]
+		}
+		return hs, nil
+	})
+}
+
+// writeTiles writes the tiles for a tree of the given size.
+func (l *testLog) writeTiles(t *testing.T, size int64) {
+	t.Helper()
+	d := dirTiles{dir: l.dir}
+	for _, tile := range tlog.NewTiles(8, 0, size) {
+		data, err := tlog.ReadTileData(tile, l.reader())
+		if err != nil {
+			t.Fatalf("ReadTileData: %v", err)
+		}
+		p := d.path(tile)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkpoint returns a checkpoint for the tree of the given size, signed by
+// the provided signers.
+func (l *testLog) checkpoint(t *testing.T, size int64, signers ...note.Signer) []byte {
+	t.Helper()
+	h, err := tlog.TreeHash(size, l.reader())
+	if err != nil {
+		t.Fatalf("TreeHash: %v", err)
+	}
+	cp := log.Checkpoint{Origin: testOrigin, Size: uint64(size), Hash: h[:]}
+	b, err := note.Sign(&note.Note{Text: string(cp.Marshal())}, signers...)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return b
+}
+
+func TestBuildVerifyPrint(t *testing.T) {
+	logSKey, logVKey, err := note.GenerateKey(rand.Reader, testOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logSigner, err := note.NewSigner(logSKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witSKey, witVKey, err := note.GenerateKey(rand.Reader, "example.com/witness")
+	if err != nil {
+		t.Fatal(err)
+	}
+	witSigner, err := f_note.NewSignerForCosignatureV1(witSKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherVKey, err := note.GenerateKey(rand.Reader, "example.com/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tiles for a tree of size 512 are written alongside those for size 300,
+	// and then the partial level 0 tile for size 300 is removed, so that
+	// proofs for later entries rely on reading the full tile in its place.
+	l := newTestLog(t, 512)
+	l.writeTiles(t, 512)
+	l.writeTiles(t, 300)
+	if err := os.Remove(filepath.Join(l.dir, "tile", "0", "001.p", "44")); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name string, b []byte) string {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	cpFile := writeFile("checkpoint", l.checkpoint(t, 300, logSigner, witSigner))
+	policy := writeFile("policy", []byte("witness w1 "+witVKey+" https://example.com/witness\nquorum w1\n"))
+
+	for _, index := range []int{0, 7, 255, 280, 299} {
+		t.Run(fmt.Sprintf("index %d", index), func(t *testing.T) {
+			leafFile := writeFile(fmt.Sprintf("leaf%d", index), leafData(index))
+			var stdout, stderr bytes.Buffer
+			args := []string{"build", "-log_vkey", logVKey, "-checkpoint", cpFile, "-tiles", l.dir, "-index", fmt.Sprint(index), "-extra", "artifact v1", "-leaf", leafFile}
+			if got := run(args, nil, &stdout, &stderr); got != exitOK {
+				t.Fatalf("build = %d, want %d: %s", got, exitOK, stderr.String())
+			}
+			proofFile := writeFile(fmt.Sprintf("proof%d", index), stdout.Bytes())
+
+			stdout.Reset()
+			if got := run([]string{"verify", "-log_vkey", logVKey, "-vkey", witVKey, "-policy", policy, "-leaf", leafFile, proofFile}, nil, &stdout, &stderr); got != exitOK {
+				t.Fatalf("verify = %d, want %d: %s", got, exitOK, stderr.String())
+			}
+
+			stdout.Reset()
+			if got := run([]string{"print", proofFile}, nil, &stdout, &stderr); got != exitOK {
+				t.Fatalf("print = %d, want %d: %s", got, exitOK, stderr.String())
+			}
+			for _, want := range []string{fmt.Sprintf("Index:       %d", index), `Extra data:  "artifact v1"`, "Origin:      " + testOrigin, "Size:        300", "Signature:   example.com/witness+"} {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("print output does not contain %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+
+	build := func(cpFile string) []byte {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if got := run([]string{"build", "-log_vkey", logVKey, "-checkpoint", cpFile, "-tiles", l.dir, "-index", "7"}, nil, &stdout, &stderr); got != exitOK {
+			t.Fatalf("build = %d, want %d: %s", got, exitOK, stderr.String())
+		}
+		return stdout.Bytes()
+	}
+	validProof := build(cpFile)
+	unwitnessed := build(writeFile("unwitnessed", l.checkpoint(t, 300, logSigner)))
+	// Dropping the first hash leaves a proof which parses, but is too short
+	// for its index and the checkpoint's size.
+	lines := strings.SplitAfter(string(validProof), "\n")
+	shortProof := []byte(strings.Join(append(lines[:2:2], lines[3:]...), ""))
+	leafHash := base64.StdEncoding.EncodeToString(log.SHA256.HashLeaf(leafData(7)))
+	otherLeafHash := base64.StdEncoding.EncodeToString(log.SHA256.HashLeaf(leafData(8)))
+
+	for _, test := range []struct {
+		desc  string
+		args  []string
+		stdin []byte
+		want  int
+	}{
+		{
+			desc:  "verify leaf hash",
+			args:  []string{"verify", "-log_vkey", logVKey, "-leaf_hash", leafHash},
+			stdin: validProof,
+			want:  exitOK,
+		},
+		{
+			desc:  "wrong leaf",
+			args:  []string{"verify", "-log_vkey", logVKey, "-leaf_hash", otherLeafHash},
+			stdin: validProof,
+			want:  exitNotIncluded,
+		},
+		{
+			desc:  "malformed proof",
+			args:  []string{"verify", "-log_vkey", logVKey, "-leaf_hash", leafHash},
+			stdin: []byte("not a proof\n"),
+			want:  exitMalformed,
+		},
+		{
+			desc:  "proof of wrong length",
+			args:  []string{"verify", "-log_vkey", logVKey, "-leaf_hash", leafHash},
+			stdin: shortProof,
+			want:  exitMalformed,
+		},
+		{
+			desc:  "wrong log key",
+			args:  []string{"verify", "-log_vkey", otherVKey, "-origin", testOrigin, "-leaf_hash", leafHash},
+			stdin: validProof,
+			want:  exitBadCheckpoint,
+		},
+		{
+			desc:  "missing required signature",
+			args:  []string{"verify", "-log_vkey", logVKey, "-vkey", otherVKey, "-leaf_hash", leafHash},
+			stdin: validProof,
+			want:  exitBadCheckpoint,
+		},
+		{
+			desc:  "policy not satisfied",
+			args:  []string{"verify", "-log_vkey", logVKey, "-policy", policy, "-leaf_hash", leafHash},
+			stdin: unwitnessed,
+			want:  exitPolicyNotSatisfied,
+		},
+		{
+			desc: "verify without leaf",
+			args: []string{"verify", "-log_vkey", logVKey},
+			want: exitUsage,
+		},
+		{
+			desc: "build index out of range",
+			args: []string{"build", "-log_vkey", logVKey, "-checkpoint", cpFile, "-tiles", l.dir, "-index", "300"},
+			want: exitError,
+		},
+		{
+			desc: "build with wrong leaf",
+			args: []string{"build", "-log_vkey", logVKey, "-checkpoint", cpFile, "-tiles", l.dir, "-index", "7", "-leaf_hash", otherLeafHash},
+			want: exitNotIncluded,
+		},
+		{
+			desc: "build with sumdb layout",
+			args: []string{"build", "-log_vkey", logVKey, "-checkpoint", cpFile, "-tiles", l.dir, "-index", "7", "-layout", "sumdb"},
+			want: exitError,
+		},
+		{
+			desc: "unknown command",
+			args: []string{"bananas"},
+			want: exitUsage,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(test.args, bytes.NewReader(test.stdin), &stdout, &stderr); got != test.want {
+				t.Errorf("tlogproof %s = %d, want %d: %s", strings.Join(test.args, " "), got, test.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestDirTiles_ReadTiles(t *testing.T) {
+	l := newTestLog(t, 256)
+	l.writeTiles(t, 256)
+	l.writeTiles(t, 100)
+	d := dirTiles{dir: l.dir}
+	full := tlog.Tile{H: 8, L: 0, N: 0, W: 256}
+	partial := tlog.Tile{H: 8, L: 0, N: 0, W: 100}
+	want, err := tlog.ReadTileData(partial, l.reader())
+	if err != nil {
+		t.Fatalf("ReadTileData: %v", err)
+	}
+
+	got, err := d.ReadTiles([]tlog.Tile{partial})
+	if err != nil {
+		t.Fatalf("ReadTiles: %v", err)
+	}
+	if !bytes.Equal(got[0], want) {
+		t.Error("ReadTiles returned wrong data for partial tile")
+	}
+
+	// Without the partial tile, the start of the full tile is used instead.
+	if err := os.Remove(d.path(partial)); err != nil {
+		t.Fatal(err)
+	}
+	got, err = d.ReadTiles([]tlog.Tile{partial})
+	if err != nil {
+		t.Fatalf("ReadTiles with full tile: %v", err)
+	}
+	if !bytes.Equal(got[0], want) {
+		t.Error("ReadTiles returned wrong data from full tile")
+	}
+
+	// A full tile which is too short to hold the partial tile is an error.
+	if err := os.WriteFile(d.path(full), want[:50*tlog.HashSize], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.ReadTiles([]tlog.Tile{partial}); err == nil || !strings.Contains(err.Error(), "too short") {
+		t.Errorf("ReadTiles with short full tile = %v, want too short error", err)
+	}
+
+	if err := os.Remove(d.path(full)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.ReadTiles([]tlog.Tile{partial}); err == nil {
+		t.Error("ReadTiles with no tile succeeded, want error")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// witnesspolicy analyses witness policy files.
+//
+// Usage:
+//
+//	witnesspolicy lint [policy file]
+//
+// Policies are read from the named file, or from stdin if no file or "-" is
+// given. Policies read from a file may include other files in the same
+// directory or below it.
+//
+// Every problem which makes a policy invalid is reported with its file, line
+// and column.
+//
+// lint reports problems with a valid policy which likely mean it is not as
+// intended, such as witnesses which are never used or which the quorum cannot
+// be satisfied without, followed by metrics describing how many witnesses
+// must collude to satisfy the quorum and how many may be unavailable.
+//
+// The exit code is:
+//
+//	0 the policy is valid, and lint found no problems
+//	1 the policy could not be read
+//	2 invalid command-line usage
+//	3 the policy is invalid
+//	4 lint found problems
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/transparency-dev/formats/cmd/internal/cli"
+	"github.com/transparency-dev/formats/witness"
+)
+
+const (
+	exitOK            = cli.ExitOK
+	exitError         = cli.ExitError
+	exitUsage         = cli.ExitUsage
+	exitInvalidPolicy = 3
+	exitLintWarnings  = 4
+)
+
+const usage = `Usage:
+  witnesspolicy lint [policy file]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	return cli.Run(args, stdin, stdout, stderr, usage, map[string]cli.Command{
+		"lint": lint,
+	})
+}
+
+// lintPolicy lints the policy in the named file, or from stdin if the name is
+// empty or "-". Policies read from stdin may not include other files.
+//
+// Each problem making the policy invalid is written to stdout on its own line.
+func lintPolicy(name string, stdin io.Reader, stdout io.Writer) (*witness.PolicyReport, error) {
+	var r *witness.PolicyReport
+	var err error
+	if name != "" && name != "-" {
+		r, err = witness.LintPolicyFS(os.DirFS(filepath.Dir(name)), filepath.Base(name))
+	} else {
+		p, rErr := io.ReadAll(stdin)
+		if rErr != nil {
+			return nil, fmt.Errorf("failed to read policy: %v", rErr)
+		}
+		r, err = witness.LintPolicy(p)
+	}
+	var pErrs witness.ParseErrors
+	switch {
+	case errors.As(err, &pErrs):
+		for _, pErr := range pErrs {
+			fmt.Fprintln(stdout, pErr)
+		}
+		return nil, cli.Fail(exitInvalidPolicy, "invalid policy: found %d errors", len(pErrs))
+	case errors.Is(err, witness.ErrNoQuorum):
+		return nil, cli.Fail(exitInvalidPolicy, "invalid policy: %v", err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to read policy: %v", err)
+	}
+	return r, nil
+}
+
+func lint(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	if err := cli.ParseFlags(fs, args, stderr, "policy file"); err != nil {
+		return err
+	}
+	r, err := lintPolicy(fs.Arg(0), stdin, stdout)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range r.Warnings {
+		fmt.Fprintln(stdout, w)
+	}
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Witnesses:\t%d\n", r.Witnesses)
+	fmt.Fprintf(w, "Minimum to satisfy quorum:\t%d\n", r.MinSatisfying)
+	fmt.Fprintf(w, "Maximum unavailable:\t%d\n", r.MaxUnavailable)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(r.Warnings) > 0 {
+		return cli.Fail(exitLintWarnings, "found %d problems", len(r.Warnings))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	wit1VKey = "Wit1+55ee4561+AVhZSmQj9+SoL+p/nN0Hh76xXmF7QcHfytUrI1XfSClk"
+	wit2VKey = "Wit2+85ecc407+AWVbwFJte9wMQIPSnEnj4KibeO6vSIOEDUTDp3o63c2x"
+	wit3VKey = "Wit3+d3ed3be7+ASb6Uz1+fxAcXkMvDd7nGa3FjDce7LxIKmbbTCT0MpVn"
+)
+
+const goodPolicy = `
+witness w1 ` + wit1VKey + ` https://w1.example.com/
+witness w2 ` + wit2VKey + ` https://w2.example.com/
+witness w3 ` + wit3VKey + ` https://w3.example.com/
+group g1 2 w1 w2 w3
+quorum g1
+`
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, contents string) string {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	policyFile := writeFile("policy", goodPolicy)
+	writeFile("witnesses", "witness w1 "+wit1VKey+" https://w1.example.com/\nwitness w2 "+wit2VKey+" https://w2.example.com/\n")
+	includingFile := writeFile("including", "include witnesses\ngroup g1 any w1 w2\nwitness w3 "+wit3VKey+" https://w3.example.com/\nquorum g1\n")
+	badIncludeFile := writeFile("bad_include", "include missing\nquorum w1\n")
+
+	for _, test := range []struct {
+		desc       string
+		args       []string
+		stdin      string
+		want       int
+		wantStdout []string
+	}{
+		{
+			desc:       "file",
+			args:       []string{"lint", policyFile},
+			want:       exitOK,
+			wantStdout: []string{"Witnesses:                  3", "Minimum to satisfy quorum:  2", "Maximum unavailable:        1"},
+		}, {
+			desc:       "stdin",
+			args:       []string{"lint"},
+			stdin:      goodPolicy,
+			want:       exitOK,
+			wantStdout: []string{"Witnesses:                  3"},
+		}, {
+			desc:  "warnings",
+			args:  []string{"lint", "-"},
+			stdin: goodPolicy + "witness w4 " + wit1VKey + " https://w1.example.com/\n",
+			want:  exitLintWarnings,
+			wantStdout: []string{
+				`line 7: unreachable: witness "w4" is not used by the quorum`,
+				`line 7: duplicate-key: witness "w4" has the same key as witness "w1"`,
+				`line 7: duplicate-url: witness "w4" has the same URL as witness "w1"`,
+				"Maximum unavailable:        1",
+			},
+		}, {
+			desc: "include",
+			args: []string{"lint", includingFile},
+			want: exitLintWarnings,
+			wantStdout: []string{
+				`including:3: unreachable: witness "w3" is not used by the quorum`,
+				"Witnesses:                  2",
+			},
+		}, {
+			desc: "missing include",
+			args: []string{"lint", badIncludeFile},
+			want: exitInvalidPolicy,
+		}, {
+			desc:  "include from stdin",
+			args:  []string{"lint"},
+			stdin: "include witnesses\nquorum w1\n",
+			want:  exitInvalidPolicy,
+		}, {
+			desc:  "invalid policy",
+			args:  []string{"lint"},
+			stdin: "quorum w1\n",
+			want:  exitInvalidPolicy,
+		}, {
+			desc:  "several errors",
+			args:  []string{"lint"},
+			stdin: "group g1 any w1\nwitness w1 " + wit1VKey + " https://w1.example.com/\nwitness w2 bad-vkey https://w2.example.com/\nquorum g1\n",
+			want:  exitInvalidPolicy,
+			wantStdout: []string{
+				`line 1, column 14: forward reference: "w1" is used before its definition at line 2`,
+				`line 3, column 12: invalid witness vkey "bad-vkey"`,
+			},
+		}, {
+			desc:  "no quorum",
+			args:  []string{"lint"},
+			stdin: "witness w1 " + wit1VKey + " https://w1.example.com/\n",
+			want:  exitInvalidPolicy,
+		}, {
+			desc: "missing file",
+			args: []string{"lint", filepath.Join(t.TempDir(), "missing")},
+			want: exitError,
+		}, {
+			desc: "too many files",
+			args: []string{"lint", policyFile, policyFile},
+			want: exitUsage,
+		}, {
+			desc: "no command",
+			want: exitUsage,
+		}, {
+			desc: "unknown command",
+			args: []string{"bananas"},
+			want: exitUsage,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr); got != test.want {
+				t.Errorf("witnesspolicy %s = %d, want %d: %s", strings.Join(test.args, " "), got, test.want, stderr.String())
+			}
+			for _, want := range test.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout does not contain %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -7,4 +7,5 @@ require (
 	github.com/google/go-cmp v0.7.0
 	golang.org/x/crypto v0.54.0
 	golang.org/x/mod v0.38.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,109 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package merkletest provides reference Merkle tree computations for use in
+// tests.
+//
+// The functions are a direct transcription of the Merkle tree definitions in
+// https://www.rfc-editor.org/rfc/rfc9162.html#section-2.1 and of their
+// generalisation to subtrees for Merkle Tree Certificates, and favour
+// clarity over efficiency.
+package merkletest
+
+import (
+	"fmt"
+
+	"github.com/transparency-dev/formats/log"
+)
+
+// Leaves returns n distinct leaves.
+func Leaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = fmt.Appendf(nil, "leaf %d", i)
+	}
+	return leaves
+}
+
+// largestPowerOfTwoBelow returns the largest power of two less than n, for
+// n > 1.
+func largestPowerOfTwoBelow(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// Root returns the Merkle tree hash of leaves.
+func Root(alg log.HashAlgorithm, leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		return alg.New().Sum(nil)
+	case 1:
+		return alg.HashLeaf(leaves[0])
+	}
+	k := largestPowerOfTwoBelow(len(leaves))
+	return alg.HashChildren(Root(alg, leaves[:k]), Root(alg, leaves[k:]))
+}
+
+// Inclusion returns the inclusion proof for the leaf at index m of leaves.
+func Inclusion(alg log.HashAlgorithm, m int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := largestPowerOfTwoBelow(len(leaves))
+	if m < k {
+		return append(Inclusion(alg, m, leaves[:k]), Root(alg, leaves[k:]))
+	}
+	return append(Inclusion(alg, m-k, leaves[k:]), Root(alg, leaves[:k]))
+}
+
+// Consistency returns the consistency proof from the first m of leaves to
+// all of them. complete is the flag b of the SUBPROOF definition, and is true
+// for a proof from a tree whose root hash is known to the verifier.
+func Consistency(alg log.HashAlgorithm, m int, leaves [][]byte, complete bool) [][]byte {
+	if m == len(leaves) {
+		if complete {
+			return nil
+		}
+		return [][]byte{Root(alg, leaves)}
+	}
+	k := largestPowerOfTwoBelow(len(leaves))
+	if m <= k {
+		return append(Consistency(alg, m, leaves[:k], complete), Root(alg, leaves[k:]))
+	}
+	return append(Consistency(alg, m-k, leaves[k:], false), Root(alg, leaves[:k]))
+}
+
+// SubtreeConsistency returns the consistency proof from the subtree
+// [start, end) of leaves to all of them, generalising Consistency to
+// subtrees not starting at zero. known is true for a proof from a subtree
+// whose hash is known to the verifier.
+func SubtreeConsistency(alg log.HashAlgorithm, start, end int, leaves [][]byte, known bool) [][]byte {
+	if start == 0 && end == len(leaves) {
+		if known {
+			return nil
+		}
+		return [][]byte{Root(alg, leaves)}
+	}
+	k := largestPowerOfTwoBelow(len(leaves))
+	switch {
+	case end <= k:
+		return append(SubtreeConsistency(alg, start, end, leaves[:k], known), Root(alg, leaves[k:]))
+	case start >= k:
+		return append(SubtreeConsistency(alg, start-k, end-k, leaves[k:], known), Root(alg, leaves[:k]))
+	}
+	return append(SubtreeConsistency(alg, 0, end-k, leaves[k:], false), Root(alg, leaves[:k]))
+}
@@ -25,6 +25,32 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxCheckpointSize is the largest checkpoint body, in bytes, which
+// UnmarshalStrict will accept.
+const MaxCheckpointSize = 16 * 1024
+
+// Errors returned by Unmarshal when a checkpoint body cannot be parsed.
+var (
+	ErrTooFewLines = errors.New("invalid checkpoint - too few newlines")
+	ErrEmptyOrigin = errors.New("invalid checkpoint - empty origin")
+	ErrInvalidSize = errors.New("invalid checkpoint - size invalid")
+	ErrInvalidHash = errors.New("invalid checkpoint - invalid hash")
+)
+
+// Errors returned by UnmarshalStrict when a checkpoint body does not follow
+// the rules in https://c2sp.org/tlog-checkpoint.
+var (
+	ErrCheckpointTooLarge = errors.New("invalid checkpoint - body too large")
+	ErrInvalidOrigin      = errors.New("invalid checkpoint - origin contains spaces or control characters")
+	ErrNonCanonicalSize   = errors.New("invalid checkpoint - size not in canonical decimal form")
+	ErrInvalidHashSize    = errors.New("invalid checkpoint - hash size does not match hash algorithm")
+	ErrNonCanonicalHash   = errors.New("invalid checkpoint - hash not in canonical base64 form")
+	ErrBlankExtensionLine = errors.New("invalid checkpoint - blank extension line")
 )
 
 // Checkpoint represents a minimal log checkpoint (STH).
@@ -52,22 +78,24 @@ func (c Checkpoint) Marshal() []byte {
 //   - <base64 representation of root hash>
 //
 // Any trailing data after this will be returned.
+//
+// Errors are of type *CheckpointError, and match ErrMalformedCheckpoint.
 func (c *Checkpoint) Unmarshal(data []byte) ([]byte, error) {
 	l := bytes.SplitN(data, []byte("\n"), 4)
 	if len(l) < 4 {
-		return nil, errors.New("invalid checkpoint - too few newlines")
+		return nil, &CheckpointError{Line: len(l), Err: ErrTooFewLines}
 	}
 	origin := string(l[0])
 	if len(origin) == 0 {
-		return nil, errors.New("invalid checkpoint - empty origin")
+		return nil, &CheckpointError{Line: 1, Err: ErrEmptyOrigin}
 	}
 	size, err := strconv.ParseUint(string(l[1]), 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid checkpoint - size invalid: %w", err)
+		return nil, &CheckpointError{Line: 2, Err: fmt.Errorf("%w: %w", ErrInvalidSize, err)}
 	}
 	h, err := base64.StdEncoding.DecodeString(string(l[2]))
 	if err != nil {
-		return nil, fmt.Errorf("invalid checkpoint - invalid hash: %w", err)
+		return nil, &CheckpointError{Line: 3, Err: fmt.Errorf("%w: %w", ErrInvalidHash, err)}
 	}
 	var rest []byte
 	if len(l[3]) > 0 {
@@ -80,3 +108,57 @@ func (c *Checkpoint) Unmarshal(data []byte) ([]byte, error) {
 	}
 	return rest, nil
 }
+
+// UnmarshalStrict behaves like Unmarshal, but additionally enforces the rules
+// of https://c2sp.org/tlog-checkpoint which Unmarshal is lenient about:
+//   - the body must be no larger than MaxCheckpointSize
+//   - the origin must not contain Unicode spaces or control characters
+//   - the size must be in canonical decimal form, without leading zeroes
+//   - the hash must be exactly 32 bytes, in canonical standard base64 form
+//   - any trailing extension lines must be non-empty
+//
+// This allows witnesses and other verifiers to refuse checkpoints which other
+// parsers may interpret differently.
+//
+// As for Unmarshal, errors are of type *CheckpointError.
+func (c *Checkpoint) UnmarshalStrict(data []byte) ([]byte, error) {
+	return c.UnmarshalStrictWithHash(data, SHA256)
+}
+
+// UnmarshalStrictWithHash behaves like UnmarshalStrict, but requires the hash
+// to be the size of those produced by the provided hash algorithm, rather
+// than SHA-256.
+func (c *Checkpoint) UnmarshalStrictWithHash(data []byte, alg HashAlgorithm) ([]byte, error) {
+	if alg.Size() == 0 {
+		return nil, fmt.Errorf("%w %v", ErrUnknownHashAlgorithm, alg)
+	}
+	if len(data) > MaxCheckpointSize {
+		return nil, &CheckpointError{Err: fmt.Errorf("%w: %d > %d bytes", ErrCheckpointTooLarge, len(data), MaxCheckpointSize)}
+	}
+	var cp Checkpoint
+	rest, err := cp.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	l := bytes.SplitN(data, []byte("\n"), 4)
+	if !utf8.ValidString(cp.Origin) || strings.IndexFunc(cp.Origin, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return nil, &CheckpointError{Line: 1, Err: fmt.Errorf("%w: %q", ErrInvalidOrigin, cp.Origin)}
+	}
+	if s := string(l[1]); strconv.FormatUint(cp.Size, 10) != s {
+		return nil, &CheckpointError{Line: 2, Err: fmt.Errorf("%w: %q", ErrNonCanonicalSize, s)}
+	}
+	if len(cp.Hash) != alg.Size() {
+		return nil, &CheckpointError{Line: 3, Err: fmt.Errorf("%w: got %d bytes, want %d for %v", ErrInvalidHashSize, len(cp.Hash), alg.Size(), alg)}
+	}
+	if h := string(l[2]); base64.StdEncoding.EncodeToString(cp.Hash) != h {
+		return nil, &CheckpointError{Line: 3, Err: fmt.Errorf("%w: %q", ErrNonCanonicalHash, h)}
+	}
+	if len(rest) > 0 && rest[0] == '\n' {
+		return nil, &CheckpointError{Line: 4, Err: ErrBlankExtensionLine}
+	}
+	if i := bytes.Index(rest, []byte("\n\n")); i >= 0 {
+		return nil, &CheckpointError{Line: 5 + bytes.Count(rest[:i], []byte("\n")), Err: ErrBlankExtensionLine}
+	}
+	*c = cp
+	return rest, nil
+}
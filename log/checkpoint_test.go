@@ -16,6 +16,7 @@ package log_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -153,6 +154,92 @@ func TestUnmarshalLogState(t *testing.T) {
 	}
 }
 
+func TestUnmarshalStrict(t *testing.T) {
+	const hash = "n3rDB+0oMhPIvHKhjiGOxVz3yP1nZlGp5PGpWDxHkLo="
+	for _, test := range []struct {
+		desc     string
+		m        string
+		wantRest []byte
+		wantErr  error
+	}{
+		{
+			desc: "valid",
+			m:    "example.com/log\n123\n" + hash + "\n",
+		}, {
+			desc:     "valid with extension lines",
+			m:        "example.com/log\n0\n" + hash + "\nsome\ndata\n",
+			wantRest: []byte("some\ndata\n"),
+		}, {
+			desc:    "too large",
+			m:       "example.com/log\n123\n" + hash + "\n" + strings.Repeat("a", log.MaxCheckpointSize) + "\n",
+			wantErr: log.ErrCheckpointTooLarge,
+		}, {
+			desc:    "origin with space",
+			m:       "Moon Log\n123\n" + hash + "\n",
+			wantErr: log.ErrInvalidOrigin,
+		}, {
+			desc:    "origin with control character",
+			m:       "example.com/log\x00\n123\n" + hash + "\n",
+			wantErr: log.ErrInvalidOrigin,
+		}, {
+			desc:    "origin with invalid UTF-8",
+			m:       "example.com/\xff\n123\n" + hash + "\n",
+			wantErr: log.ErrInvalidOrigin,
+		}, {
+			desc:    "size with leading zeroes",
+			m:       "example.com/log\n007\n" + hash + "\n",
+			wantErr: log.ErrNonCanonicalSize,
+		}, {
+			desc:    "zero size with leading zeroes",
+			m:       "example.com/log\n00\n" + hash + "\n",
+			wantErr: log.ErrNonCanonicalSize,
+		}, {
+			desc:    "short hash",
+			m:       "example.com/log\n123\nYmFuYW5hcw==\n",
+			wantErr: log.ErrInvalidHashSize,
+		}, {
+			desc:    "hash with embedded carriage return",
+			m:       "example.com/log\n123\n" + hash[:10] + "\r" + hash[10:] + "\n",
+			wantErr: log.ErrNonCanonicalHash,
+		}, {
+			desc:    "hash with non-zero padding bits",
+			m:       "example.com/log\n123\n" + hash[:len(hash)-2] + "p=\n",
+			wantErr: log.ErrNonCanonicalHash,
+		}, {
+			desc:    "trailing blank lines",
+			m:       "example.com/log\n123\n" + hash + "\n\n\n",
+			wantErr: log.ErrBlankExtensionLine,
+		}, {
+			desc:    "blank extension line",
+			m:       "example.com/log\n123\n" + hash + "\nsome\n\ndata\n",
+			wantErr: log.ErrBlankExtensionLine,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			// Every case must be acceptable to the lenient parser.
+			var lenient log.Checkpoint
+			if _, err := lenient.Unmarshal([]byte(test.m)); err != nil {
+				t.Fatalf("Unmarshal = %v", err)
+			}
+
+			var got log.Checkpoint
+			rest, err := got.UnmarshalStrict([]byte(test.m))
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("UnmarshalStrict = %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(lenient, got); len(diff) != 0 {
+				t.Errorf("Unmarshalled Checkpoint with diff %s", diff)
+			}
+			if !bytes.Equal(test.wantRest, rest) {
+				t.Errorf("got rest %q, want %q", rest, test.wantRest)
+			}
+		})
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Below is an example of embedding the minimal checkpoint as one way to extend
 // it to include additional ecosystem-specific data.
@@ -0,0 +1,83 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMalformedCheckpoint matches, using errors.Is, every error returned
+	// because a checkpoint body could not be parsed. Such errors are of type
+	// *CheckpointError.
+	ErrMalformedCheckpoint = errors.New("malformed checkpoint")
+	// ErrNoLogSignature is returned when a checkpoint has no valid signature
+	// from the log's key, although it may have valid signatures from others.
+	ErrNoLogSignature = errors.New("no log signature found on note")
+)
+
+// CheckpointError describes why a checkpoint body could not be parsed.
+//
+// errors.Is reports a CheckpointError as matching ErrMalformedCheckpoint, in
+// addition to the error it wraps.
+type CheckpointError struct {
+	// Line is the 1-based line number of the body which is at fault, or 0 if
+	// the error does not relate to a single line.
+	Line int
+	// Err describes the problem, and is typically one of the sentinel errors
+	// defined in this package.
+	Err error
+}
+
+func (e *CheckpointError) Error() string {
+	if e.Line == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (line %d)", e.Err, e.Line)
+}
+
+func (e *CheckpointError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrMalformedCheckpoint.
+func (e *CheckpointError) Is(target error) bool { return target == ErrMalformedCheckpoint }
+
+// OriginMismatchError is returned when a checkpoint is for a different log
+// than the one expected.
+type OriginMismatchError struct {
+	Got  string
+	Want string
+}
+
+func (e *OriginMismatchError) Error() string {
+	return fmt.Sprintf("got Origin %q but expected %q", e.Got, e.Want)
+}
+
+// SignatureError describes a problem with the signatures on a checkpoint
+// which relates to a particular key.
+type SignatureError struct {
+	// Name and KeyHash identify the key.
+	Name    string
+	KeyHash uint32
+	// Err describes the problem, and is typically one of the sentinel errors
+	// defined in this package.
+	Err error
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("%v: %s+%08x", e.Err, e.Name, e.KeyHash)
+}
+
+func (e *SignatureError) Unwrap() error { return e.Err }
@@ -0,0 +1,132 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/transparency-dev/formats/internal/merkletest"
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+)
+
+func TestCheckpointError(t *testing.T) {
+	const hash = "n3rDB+0oMhPIvHKhjiGOxVz3yP1nZlGp5PGpWDxHkLo="
+	for _, test := range []struct {
+		desc     string
+		m        string
+		strict   bool
+		wantLine int
+		wantErr  error
+	}{
+		{desc: "too few lines", m: "origin\n1\n", wantLine: 3, wantErr: log.ErrTooFewLines},
+		{desc: "empty origin", m: "\n1\n" + hash + "\n", wantLine: 1, wantErr: log.ErrEmptyOrigin},
+		{desc: "bad size", m: "origin\n-1\n" + hash + "\n", wantLine: 2, wantErr: log.ErrInvalidSize},
+		{desc: "bad hash", m: "origin\n1\n!!\n", wantLine: 3, wantErr: log.ErrInvalidHash},
+		{desc: "non-canonical size", m: "origin\n01\n" + hash + "\n", strict: true, wantLine: 2, wantErr: log.ErrNonCanonicalSize},
+		{desc: "first extension line blank", m: "origin\n1\n" + hash + "\n\nb\n", strict: true, wantLine: 4, wantErr: log.ErrBlankExtensionLine},
+		{desc: "later extension line blank", m: "origin\n1\n" + hash + "\na\nb\n\nc\n", strict: true, wantLine: 6, wantErr: log.ErrBlankExtensionLine},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var cp log.Checkpoint
+			var err error
+			if test.strict {
+				_, err = cp.UnmarshalStrict([]byte(test.m))
+			} else {
+				_, err = cp.Unmarshal([]byte(test.m))
+			}
+			var cErr *log.CheckpointError
+			if !errors.As(err, &cErr) {
+				t.Fatalf("got error %v, want a *CheckpointError", err)
+			}
+			if cErr.Line != test.wantLine {
+				t.Errorf("got line %d, want %d", cErr.Line, test.wantLine)
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("got error %v, want %v", err, test.wantErr)
+			}
+			if !errors.Is(err, log.ErrMalformedCheckpoint) {
+				t.Errorf("error %v does not match ErrMalformedCheckpoint", err)
+			}
+		})
+	}
+}
+
+func TestParseCheckpointErrors(t *testing.T) {
+	logVerifier, err := note.NewVerifier(logVK)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	known1Verifier, err := note.NewVerifier(known1VK)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	cp := log.Checkpoint{Origin: "TestParseCheckpointErrors", Size: 1, Hash: []byte("root")}
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, _, _, err := log.ParseCheckpoint(signCheckpoint(t, cp, unknownSK), cp.Origin, logVerifier)
+		var uErr *note.UnverifiedNoteError
+		if !errors.As(err, &uErr) {
+			t.Errorf("got error %v, want a *note.UnverifiedNoteError", err)
+		}
+	})
+	t.Run("bad signature", func(t *testing.T) {
+		signed := signCheckpoint(t, cp, logSK)
+		signed[0] = 'X'
+		_, _, _, err := log.ParseCheckpoint(signed, cp.Origin, logVerifier)
+		var sErr *note.InvalidSignatureError
+		if !errors.As(err, &sErr) {
+			t.Errorf("got error %v, want a *note.InvalidSignatureError", err)
+		}
+	})
+	t.Run("wrong origin", func(t *testing.T) {
+		_, _, _, err := log.ParseCheckpoint(signCheckpoint(t, cp, logSK), "other", logVerifier)
+		var oErr *log.OriginMismatchError
+		if !errors.As(err, &oErr) {
+			t.Fatalf("got error %v, want a *OriginMismatchError", err)
+		}
+		if oErr.Got != cp.Origin || oErr.Want != "other" {
+			t.Errorf("got %+v", oErr)
+		}
+	})
+	t.Run("no log signature", func(t *testing.T) {
+		_, _, _, err := log.ParseCheckpoint(signCheckpoint(t, cp, known1SK), cp.Origin, logVerifier, known1Verifier)
+		var sErr *log.SignatureError
+		if !errors.As(err, &sErr) {
+			t.Fatalf("got error %v, want a *SignatureError", err)
+		}
+		if sErr.KeyHash != logVerifier.KeyHash() || !errors.Is(err, log.ErrNoLogSignature) {
+			t.Errorf("got %+v", sErr)
+		}
+	})
+}
+
+func TestMerkleProofErrors(t *testing.T) {
+	leaves := merkletest.Leaves(5)
+	root := merkletest.Root(log.SHA256, leaves)
+	proof := merkletest.Inclusion(log.SHA256, 2, leaves)
+	leafHash := log.SHA256.HashLeaf(leaves[2])
+
+	if err := log.VerifyInclusion(log.SHA256, 2, 5, leafHash, proof, merkletest.Root(log.SHA256, leaves[:4])); !errors.Is(err, log.ErrRootMismatch) {
+		t.Errorf("VerifyInclusion with wrong root = %v, want %v", err, log.ErrRootMismatch)
+	}
+	if err := log.VerifyInclusion(log.SHA256, 2, 5, leafHash, proof[1:], root); !errors.Is(err, log.ErrMalformedProof) {
+		t.Errorf("VerifyInclusion with short proof = %v, want %v", err, log.ErrMalformedProof)
+	}
+	if err := log.VerifyInclusion(log.HashAlgorithm(99), 2, 5, leafHash, proof, root); !errors.Is(err, log.ErrUnknownHashAlgorithm) {
+		t.Errorf("VerifyInclusion with unknown algorithm = %v, want %v", err, log.ErrUnknownHashAlgorithm)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/sumdb/note"
+)
+
+const (
+	splitViewHeaderV1 = "split-view/v1"
+	sigLinePrefix     = "— "
+)
+
+// SplitViewEvidence is a portable record of a log presenting two conflicting
+// views of its contents, suitable for reporting to the log operator and to
+// the wider ecosystem.
+//
+// The two checkpoints conflict if either they are for the same tree size but
+// have different root hashes, or the consistency proof shows that the
+// smaller tree is not a prefix of the larger one.
+//
+// The marshalled form is:
+//
+//	split-view/v1
+//	<zero or more base64 encoded consistency proof hashes, one per line>
+//	<blank line>
+//	<first signed checkpoint note>
+//	<second signed checkpoint note>
+//
+// The first note ends with the last of the signature lines which follow its
+// blank separator line.
+type SplitViewEvidence struct {
+	// Checkpoint1 and Checkpoint2 are the two conflicting signed checkpoints.
+	Checkpoint1 []byte
+	Checkpoint2 []byte
+	// ConsistencyProof is required when the checkpoints are for different
+	// tree sizes. It is a consistency proof, as described in
+	// https://www.rfc-editor.org/rfc/rfc9162.html#section-2.1.4, from the
+	// smaller tree size to the larger, which verifies against the root hash
+	// of the larger checkpoint but not that of the smaller one.
+	//
+	// Where the smaller tree size is a power of two, RFC 9162 omits that
+	// tree's root hash from the proof since the verifier already knows it.
+	// Here, the proof must instead begin with the root hash of the prefix of
+	// the larger tree.
+	ConsistencyProof [][]byte
+}
+
+// NewSplitViewEvidence returns evidence of the equivocation described by the
+// provided event, which must be of kind EventEquivocation and have the signed
+// notes of both checkpoints available.
+func NewSplitViewEvidence(e Event) (*SplitViewEvidence, error) {
+	if e.Kind != EventEquivocation {
+		return nil, fmt.Errorf("event of kind %v is not an equivocation", e.Kind)
+	}
+	if e.Previous == nil || e.Previous.Signed == nil || e.Current.Signed == nil {
+		return nil, errors.New("signed checkpoints not available for event")
+	}
+	return &SplitViewEvidence{
+		Checkpoint1: e.Previous.Signed,
+		Checkpoint2: e.Current.Signed,
+	}, nil
+}
+
+// Marshal returns the marshalled form of the evidence.
+func (e SplitViewEvidence) Marshal() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n", splitViewHeaderV1)
+	for _, h := range e.ConsistencyProof {
+		fmt.Fprintf(&b, "%s\n", base64.StdEncoding.EncodeToString(h))
+	}
+	b.WriteByte('\n')
+	b.Write(e.Checkpoint1)
+	b.Write(e.Checkpoint2)
+	return b.Bytes()
+}
+
+// Unmarshal parses the marshalled form of the evidence and stores the result
+// in the SplitViewEvidence. No verification is performed, see Verify.
+func (e *SplitViewEvidence) Unmarshal(data []byte) error {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	if s.Scan(); s.Text() != splitViewHeaderV1 {
+		return errors.New("split view evidence missing expected header")
+	}
+
+	var proof [][]byte
+	for s.Scan() && s.Text() != "" {
+		h, err := base64.StdEncoding.DecodeString(s.Text())
+		if err != nil {
+			return fmt.Errorf("split view evidence hash not base64 encoded: %w", err)
+		}
+		proof = append(proof, h)
+	}
+
+	// The first note runs until the end of the signature lines which follow
+	// its blank separator line.
+	var cp1, cp2 bytes.Buffer
+	cur, blank, sigs := &cp1, false, 0
+	for s.Scan() {
+		l := s.Text()
+		if cur == &cp1 {
+			switch {
+			case !blank:
+				blank = l == ""
+			case strings.HasPrefix(l, sigLinePrefix):
+				sigs++
+			case sigs > 0:
+				cur = &cp2
+			default:
+				return errors.New("split view evidence first checkpoint has no signatures")
+			}
+		}
+		cur.WriteString(l)
+		cur.WriteByte('\n')
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("scanning split view evidence: %w", err)
+	}
+	if cp2.Len() == 0 {
+		return errors.New("split view evidence missing second checkpoint")
+	}
+
+	e.Checkpoint1 = cp1.Bytes()
+	e.Checkpoint2 = cp2.Bytes()
+	e.ConsistencyProof = proof
+	return nil
+}
+
+// Verify checks that the evidence proves that the log identified by origin
+// and logVerifier has equivocated.
+//
+// Both checkpoints are independently verified using ParseCheckpoint, and
+// must then either be for the same tree size with different root hashes, or
+// be shown to be inconsistent by the consistency proof, which is evaluated
+// using the provided hash algorithm.
+func (e SplitViewEvidence) Verify(alg HashAlgorithm, origin string, logVerifier note.Verifier) error {
+	cp1, _, _, err := ParseCheckpoint(e.Checkpoint1, origin, logVerifier)
+	if err != nil {
+		return fmt.Errorf("invalid first checkpoint: %w", err)
+	}
+	cp2, _, _, err := ParseCheckpoint(e.Checkpoint2, origin, logVerifier)
+	if err != nil {
+		return fmt.Errorf("invalid second checkpoint: %w", err)
+	}
+	if err := checkHashSizes(alg, append([][]byte{cp1.Hash, cp2.Hash}, e.ConsistencyProof...)); err != nil {
+		return err
+	}
+	if cp1.Size > cp2.Size {
+		cp1, cp2 = cp2, cp1
+	}
+
+	switch {
+	case cp1.Size == cp2.Size:
+		if len(e.ConsistencyProof) > 0 {
+			return errors.New("consistency proof given for checkpoints of the same size")
+		}
+		if bytes.Equal(cp1.Hash, cp2.Hash) {
+			return fmt.Errorf("checkpoints for size %d have the same root hash", cp1.Size)
+		}
+		return nil
+	case cp1.Size == 0:
+		return errors.New("a checkpoint for an empty tree cannot conflict with a larger checkpoint")
+	case len(e.ConsistencyProof) == 0:
+		return fmt.Errorf("consistency proof required for checkpoints of sizes %d and %d", cp1.Size, cp2.Size)
+	}
+
+	r1, r2, err := rootsFromConsistencyProof(alg, cp1.Size, cp2.Size, e.ConsistencyProof)
+	if err != nil {
+		return fmt.Errorf("invalid consistency proof: %w", err)
+	}
+	if !bytes.Equal(r2, cp2.Hash) {
+		return fmt.Errorf("consistency proof does not verify against root hash of checkpoint at size %d", cp2.Size)
+	}
+	if bytes.Equal(r1, cp1.Hash) {
+		return fmt.Errorf("checkpoints for sizes %d and %d are consistent", cp1.Size, cp2.Size)
+	}
+	return nil
+}
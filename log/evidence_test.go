@@ -0,0 +1,187 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/transparency-dev/formats/internal/merkletest"
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+)
+
+func signCheckpoint(t *testing.T, cp log.Checkpoint, skey string) []byte {
+	t.Helper()
+	s, err := note.NewSigner(skey)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	n, err := note.Sign(&note.Note{Text: string(cp.Marshal())}, s)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return n
+}
+
+func TestSplitViewEvidence(t *testing.T) {
+	const origin = "Log"
+	logVerifier, err := note.NewVerifier(logVK)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	leaves := merkletest.Leaves(9)
+	// forked shares the first 3 leaves with leaves, and differs thereafter.
+	forked := append(merkletest.Leaves(3), [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e"), []byte("f")}...)
+	signedAt := func(l [][]byte, size int, skey string) []byte {
+		return signCheckpoint(t, log.Checkpoint{Origin: origin, Size: uint64(size), Hash: merkletest.Root(log.SHA256, l[:size])}, skey)
+	}
+
+	for _, test := range []struct {
+		desc    string
+		e       log.SplitViewEvidence
+		wantErr bool
+	}{
+		{
+			desc: "same size different roots",
+			e: log.SplitViewEvidence{
+				Checkpoint1: signedAt(leaves, 7, logSK),
+				Checkpoint2: signedAt(forked, 7, logSK),
+			},
+		}, {
+			desc: "different sizes inconsistent",
+			e: log.SplitViewEvidence{
+				Checkpoint1: signedAt(leaves, 5, logSK),
+				Checkpoint2: signedAt(forked, 9, logSK),
+				// A proof from the forked tree shows its size 5 prefix differs.
+				ConsistencyProof: merkletest.Consistency(log.SHA256, 5, forked, true),
+			},
+		}, {
+			desc: "different sizes inconsistent in reverse order",
+			e: log.SplitViewEvidence{
+				Checkpoint1:      signedAt(forked, 9, logSK),
+				Checkpoint2:      signedAt(leaves, 5, logSK),
+				ConsistencyProof: merkletest.Consistency(log.SHA256, 5, forked, true),
+			},
+		}, {
+			desc: "different sizes inconsistent with power of two",
+			e: log.SplitViewEvidence{
+				Checkpoint1:      signedAt(leaves, 4, logSK),
+				Checkpoint2:      signedAt(forked, 9, logSK),
+				ConsistencyProof: merkletest.Consistency(log.SHA256, 4, forked, false),
+			},
+		}, {
+			desc: "same size same roots",
+			e: log.SplitViewEvidence{
+				Checkpoint1: signedAt(leaves, 7, logSK),
+				Checkpoint2: signedAt(leaves, 7, logSK),
+			},
+			wantErr: true,
+		}, {
+			desc: "different sizes consistent",
+			e: log.SplitViewEvidence{
+				Checkpoint1:      signedAt(leaves, 5, logSK),
+				Checkpoint2:      signedAt(leaves, 9, logSK),
+				ConsistencyProof: merkletest.Consistency(log.SHA256, 5, leaves, true),
+			},
+			wantErr: true,
+		}, {
+			desc: "different sizes without proof",
+			e: log.SplitViewEvidence{
+				Checkpoint1: signedAt(leaves, 5, logSK),
+				Checkpoint2: signedAt(forked, 9, logSK),
+			},
+			wantErr: true,
+		}, {
+			desc: "proof not for larger checkpoint",
+			e: log.SplitViewEvidence{
+				Checkpoint1:      signedAt(leaves, 5, logSK),
+				Checkpoint2:      signedAt(leaves, 9, logSK),
+				ConsistencyProof: merkletest.Consistency(log.SHA256, 5, forked, true),
+			},
+			wantErr: true,
+		}, {
+			desc: "not signed by log",
+			e: log.SplitViewEvidence{
+				Checkpoint1: signedAt(leaves, 7, logSK),
+				Checkpoint2: signedAt(forked, 7, known1SK),
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var got log.SplitViewEvidence
+			if err := got.Unmarshal(test.e.Marshal()); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if diff := cmp.Diff(test.e, got); diff != "" {
+				t.Errorf("round trip diff: %s", diff)
+			}
+			err := got.Verify(log.SHA256, origin, logVerifier)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("Verify = %v, wantErr %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitViewEvidence_UnmarshalErrors(t *testing.T) {
+	cp := "Log\n1\nYmFuYW5hcw==\n\n— Log c2ln\n"
+	for _, test := range []struct {
+		desc string
+		data string
+	}{
+		{desc: "missing header", data: "\n" + cp + cp},
+		{desc: "invalid proof hash", data: "split-view/v1\n!!\n\n" + cp + cp},
+		{desc: "missing second checkpoint", data: "split-view/v1\n\n" + cp},
+		{desc: "first checkpoint unsigned", data: "split-view/v1\n\nLog\n1\nYmFuYW5hcw==\n\n" + cp},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var e log.SplitViewEvidence
+			if err := e.Unmarshal([]byte(test.data)); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestNewSplitViewEvidence(t *testing.T) {
+	logVerifier, err := note.NewVerifier(logVK)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	tr := log.NewTracker(log.NewMemoryCheckpointStore(), log.SHA256)
+	cp1 := log.Checkpoint{Origin: "Log", Size: 3, Hash: merkletest.Root(log.SHA256, merkletest.Leaves(3))}
+	cp2 := log.Checkpoint{Origin: "Log", Size: 3, Hash: merkletest.Root(log.SHA256, merkletest.Leaves(4)[1:])}
+
+	e, err := tr.Update(cp1, signCheckpoint(t, cp1, logSK), nil)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := log.NewSplitViewEvidence(e); err == nil {
+		t.Error("NewSplitViewEvidence succeeded for non-equivocation event")
+	}
+	e, err = tr.Update(cp2, signCheckpoint(t, cp2, logSK), nil)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	ev, err := log.NewSplitViewEvidence(e)
+	if err != nil {
+		t.Fatalf("NewSplitViewEvidence: %v", err)
+	}
+	if err := ev.Verify(log.SHA256, "Log", logVerifier); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ExtensionCodec knows how to convert a single checkpoint extension line to
+// and from a typed value.
+type ExtensionCodec interface {
+	// Unmarshal parses the provided extension line, which does not include the
+	// trailing newline.
+	Unmarshal(line string) (any, error)
+	// Marshal returns the extension line representation of v, without a
+	// trailing newline.
+	Marshal(v any) (string, error)
+}
+
+// Extension is a single line of checkpoint extension data ("otherdata").
+type Extension struct {
+	// Prefix is the registered prefix which matched this line, or empty if no
+	// codec matched.
+	Prefix string
+	// Value is the typed value returned by the matching codec, or nil if no
+	// codec matched.
+	Value any
+	// Line is the raw extension line, without the trailing newline.
+	// For lines with a Value, this is ignored when marshalling.
+	Line string
+}
+
+// ExtensionRegistry maps extension line prefixes to the codecs which handle
+// them. Ecosystems which use checkpoint extension lines will typically create
+// a single registry which knows about all the lines they define.
+//
+// The zero value is an empty, non-strict, registry.
+type ExtensionRegistry struct {
+	// Strict causes blank extension lines to be rejected. Such lines are not
+	// permitted in the body of a signed note, but Checkpoint.Unmarshal accepts
+	// them.
+	Strict bool
+
+	codecs map[string]ExtensionCodec
+}
+
+// Register adds a codec which handles extension lines starting with prefix.
+// Where more than one registered prefix matches a line, the longest is used.
+func (r *ExtensionRegistry) Register(prefix string, c ExtensionCodec) error {
+	if prefix == "" || strings.Contains(prefix, "\n") {
+		return fmt.Errorf("invalid extension prefix %q", prefix)
+	}
+	if _, ok := r.codecs[prefix]; ok {
+		return fmt.Errorf("duplicate extension prefix %q", prefix)
+	}
+	if r.codecs == nil {
+		r.codecs = make(map[string]ExtensionCodec)
+	}
+	r.codecs[prefix] = c
+	return nil
+}
+
+// lookup returns the longest registered prefix matching the line, along with
+// its codec.
+func (r *ExtensionRegistry) lookup(line string) (string, ExtensionCodec) {
+	var prefix string
+	var codec ExtensionCodec
+	for p, c := range r.codecs {
+		if strings.HasPrefix(line, p) && len(p) > len(prefix) {
+			prefix, codec = p, c
+		}
+	}
+	return prefix, codec
+}
+
+// Parse converts checkpoint extension data, as returned by
+// Checkpoint.Unmarshal, into a list of extensions.
+//
+// Lines which do not match any registered prefix are preserved as-is.
+// Lines which do match are required to be in the canonical form produced by
+// their codec, so that marshalling the result reproduces the input exactly.
+func (r *ExtensionRegistry) Parse(rest []byte) ([]Extension, error) {
+	if len(rest) == 0 {
+		return nil, nil
+	}
+	if !bytes.HasSuffix(rest, []byte("\n")) {
+		return nil, errors.New("invalid extension data - missing trailing newline")
+	}
+	lines := strings.Split(string(rest[:len(rest)-1]), "\n")
+	exts := make([]Extension, 0, len(lines))
+	for i, l := range lines {
+		if l == "" && r.Strict {
+			return nil, fmt.Errorf("invalid extension data - blank line %d", i+1)
+		}
+		prefix, c := r.lookup(l)
+		if c == nil {
+			exts = append(exts, Extension{Line: l})
+			continue
+		}
+		v, err := c.Unmarshal(l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extension line %d with prefix %q: %w", i+1, prefix, err)
+		}
+		if m, err := c.Marshal(v); err != nil || m != l {
+			return nil, fmt.Errorf("invalid extension line %d with prefix %q - not in canonical form", i+1, prefix)
+		}
+		exts = append(exts, Extension{Prefix: prefix, Value: v, Line: l})
+	}
+	return exts, nil
+}
+
+// Marshal returns the extension data representation of the provided
+// extensions, suitable for appending to a marshalled Checkpoint.
+func (r *ExtensionRegistry) Marshal(exts []Extension) ([]byte, error) {
+	var b bytes.Buffer
+	for i, e := range exts {
+		l := e.Line
+		if e.Value != nil {
+			c, ok := r.codecs[e.Prefix]
+			if !ok {
+				return nil, fmt.Errorf("no codec registered for extension %d with prefix %q", i, e.Prefix)
+			}
+			var err error
+			if l, err = c.Marshal(e.Value); err != nil {
+				return nil, fmt.Errorf("failed to marshal extension %d with prefix %q: %w", i, e.Prefix, err)
+			}
+			if !strings.HasPrefix(l, e.Prefix) {
+				return nil, fmt.Errorf("marshalled extension %d does not start with prefix %q", i, e.Prefix)
+			}
+		}
+		if strings.Contains(l, "\n") {
+			return nil, fmt.Errorf("extension %d contains a newline", i)
+		}
+		if l == "" && r.Strict {
+			return nil, fmt.Errorf("extension %d is blank", i)
+		}
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalWithExtensions behaves like Unmarshal, but additionally parses any
+// trailing data into extensions using the provided registry.
+func (c *Checkpoint) UnmarshalWithExtensions(data []byte, r *ExtensionRegistry) ([]Extension, error) {
+	var cp Checkpoint
+	rest, err := cp.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	exts, err := r.Parse(rest)
+	if err != nil {
+		return nil, err
+	}
+	*c = cp
+	return exts, nil
+}
+
+// MarshalWithExtensions returns the common format representation of this
+// Checkpoint followed by the provided extensions.
+func (c Checkpoint) MarshalWithExtensions(exts []Extension, r *ExtensionRegistry) ([]byte, error) {
+	rest, err := r.Marshal(exts)
+	if err != nil {
+		return nil, err
+	}
+	return append(c.Marshal(), rest...), nil
+}
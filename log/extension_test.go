@@ -0,0 +1,233 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/transparency-dev/formats/log"
+)
+
+// timestampCodec handles "timestamp <hex>" extension lines.
+type timestampCodec struct{}
+
+func (timestampCodec) Unmarshal(line string) (any, error) {
+	return strconv.ParseUint(strings.TrimPrefix(line, "timestamp "), 16, 64)
+}
+
+func (timestampCodec) Marshal(v any) (string, error) {
+	ts, ok := v.(uint64)
+	if !ok {
+		return "", fmt.Errorf("got %T, want uint64", v)
+	}
+	return fmt.Sprintf("timestamp %x", ts), nil
+}
+
+// phaseCodec handles "phase <description>" extension lines.
+type phaseCodec struct{}
+
+func (phaseCodec) Unmarshal(line string) (any, error) {
+	return strings.TrimPrefix(line, "phase "), nil
+}
+
+func (phaseCodec) Marshal(v any) (string, error) {
+	p, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("got %T, want string", v)
+	}
+	return "phase " + p, nil
+}
+
+func moonRegistry(t *testing.T, strict bool) *log.ExtensionRegistry {
+	t.Helper()
+	r := &log.ExtensionRegistry{Strict: strict}
+	if err := r.Register("timestamp ", timestampCodec{}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := r.Register("phase ", phaseCodec{}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return r
+}
+
+func TestUnmarshalWithExtensions(t *testing.T) {
+	const body = "Moon Log\n4027504\naXQncyBhIHJvb3QgaGFzaA==\n"
+	wantCP := log.Checkpoint{
+		Origin: "Moon Log",
+		Size:   4027504,
+		Hash:   []byte("it's a root hash"),
+	}
+	for _, test := range []struct {
+		desc     string
+		m        string
+		strict   bool
+		want     []log.Extension
+		wantErr  bool
+		wantSame bool
+	}{
+		{
+			desc: "no extensions",
+			m:    body,
+		}, {
+			desc: "known extensions",
+			m:    body + "timestamp 6086d1a9\nphase Waxing gibbous\n",
+			want: []log.Extension{
+				{Prefix: "timestamp ", Value: uint64(0x6086d1a9), Line: "timestamp 6086d1a9"},
+				{Prefix: "phase ", Value: "Waxing gibbous", Line: "phase Waxing gibbous"},
+			},
+		}, {
+			desc: "unknown extensions are preserved",
+			m:    body + "tide high\ntimestamp 6086d1a9\n",
+			want: []log.Extension{
+				{Line: "tide high"},
+				{Prefix: "timestamp ", Value: uint64(0x6086d1a9), Line: "timestamp 6086d1a9"},
+			},
+		}, {
+			desc: "trailing blank lines are preserved",
+			m:    body + "phase New\n\n\n",
+			want: []log.Extension{
+				{Prefix: "phase ", Value: "New", Line: "phase New"},
+				{},
+				{},
+			},
+		}, {
+			desc:    "strict rejects trailing blank lines",
+			m:       body + "phase New\n\n\n",
+			strict:  true,
+			wantErr: true,
+		}, {
+			desc:    "invalid known extension",
+			m:       body + "timestamp bananas\n",
+			wantErr: true,
+		}, {
+			desc:    "non-canonical known extension",
+			m:       body + "timestamp 06086d1a9\n",
+			wantErr: true,
+		}, {
+			desc:    "missing trailing newline",
+			m:       body + "phase New",
+			wantErr: true,
+		}, {
+			desc:    "invalid checkpoint",
+			m:       "Moon Log\n4027504\n",
+			wantErr: true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			r := moonRegistry(t, test.strict)
+			var cp log.Checkpoint
+			exts, err := cp.UnmarshalWithExtensions([]byte(test.m), r)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("UnmarshalWithExtensions = %v, wantErr: %t", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if diff := cmp.Diff(wantCP, cp); diff != "" {
+				t.Errorf("Unmarshalled Checkpoint with diff %s", diff)
+			}
+			if diff := cmp.Diff(test.want, exts); diff != "" {
+				t.Errorf("Unmarshalled extensions with diff %s", diff)
+			}
+
+			// Marshalling the result must give back exactly what we started with.
+			got, err := cp.MarshalWithExtensions(exts, r)
+			if err != nil {
+				t.Fatalf("MarshalWithExtensions: %v", err)
+			}
+			if string(got) != test.m {
+				t.Errorf("MarshalWithExtensions = %q, want %q", got, test.m)
+			}
+		})
+	}
+}
+
+func TestMarshalWithExtensions(t *testing.T) {
+	cp := log.Checkpoint{
+		Origin: "Moon Log",
+		Size:   4027504,
+		Hash:   []byte("it's a root hash"),
+	}
+	for _, test := range []struct {
+		desc    string
+		exts    []log.Extension
+		strict  bool
+		want    string
+		wantErr bool
+	}{
+		{
+			desc: "values take precedence over lines",
+			exts: []log.Extension{
+				{Prefix: "timestamp ", Value: uint64(0x6086d1aa), Line: "timestamp 6086d1a9"},
+				{Line: "tide low"},
+			},
+			want: "Moon Log\n4027504\naXQncyBhIHJvb3QgaGFzaA==\ntimestamp 6086d1aa\ntide low\n",
+		}, {
+			desc:    "unregistered prefix",
+			exts:    []log.Extension{{Prefix: "tide ", Value: "low"}},
+			wantErr: true,
+		}, {
+			desc:    "wrong value type",
+			exts:    []log.Extension{{Prefix: "timestamp ", Value: "yesterday"}},
+			wantErr: true,
+		}, {
+			desc:    "line with newline",
+			exts:    []log.Extension{{Line: "tide\nlow"}},
+			wantErr: true,
+		}, {
+			desc:    "strict blank line",
+			exts:    []log.Extension{{Line: ""}},
+			strict:  true,
+			wantErr: true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := cp.MarshalWithExtensions(test.exts, moonRegistry(t, test.strict))
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("MarshalWithExtensions = %v, wantErr: %t", err, test.wantErr)
+			}
+			if string(got) != test.want {
+				t.Errorf("MarshalWithExtensions = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExtensionRegistry_Register(t *testing.T) {
+	r := &log.ExtensionRegistry{}
+	if err := r.Register("phase ", phaseCodec{}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := r.Register("phase ", phaseCodec{}); err == nil {
+		t.Error("Register of duplicate prefix succeeded")
+	}
+	if err := r.Register("", phaseCodec{}); err == nil {
+		t.Error("Register of empty prefix succeeded")
+	}
+	if err := r.Register("pha\nse", phaseCodec{}); err == nil {
+		t.Error("Register of prefix with newline succeeded")
+	}
+	// The longest matching prefix wins.
+	if err := r.Register("phase full", timestampCodec{}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := r.Parse([]byte("phase full moon\n")); err == nil {
+		t.Error("Parse used shorter prefix")
+	}
+}
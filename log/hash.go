@@ -0,0 +1,113 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrUnknownHashAlgorithm is returned when a hash algorithm is not supported.
+var ErrUnknownHashAlgorithm = errors.New("unknown hash algorithm")
+
+// HashAlgorithm identifies the hash function used to build a log's Merkle
+// tree, and so determines the size of the root hash in its checkpoints and of
+// the hashes in its proofs.
+//
+// The zero value is SHA256, which is used by the vast majority of logs.
+type HashAlgorithm uint8
+
+const (
+	SHA256 HashAlgorithm = iota
+	SHA384
+	SHA512_256
+)
+
+var hashAlgorithmNames = map[HashAlgorithm]string{
+	SHA256:     "sha256",
+	SHA384:     "sha384",
+	SHA512_256: "sha512_256",
+}
+
+// ParseHashAlgorithm returns the HashAlgorithm with the given name, as
+// returned by String.
+func ParseHashAlgorithm(name string) (HashAlgorithm, error) {
+	for a, n := range hashAlgorithmNames {
+		if n == name {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownHashAlgorithm, name)
+}
+
+// String returns the name of the hash algorithm.
+func (a HashAlgorithm) String() string {
+	if n, ok := hashAlgorithmNames[a]; ok {
+		return n
+	}
+	return fmt.Sprintf("HashAlgorithm(%d)", uint8(a))
+}
+
+// Size returns the length, in bytes, of hashes produced by this algorithm, or
+// zero if the algorithm is unknown.
+func (a HashAlgorithm) Size() int {
+	switch a {
+	case SHA256:
+		return sha256.Size
+	case SHA384:
+		return sha512.Size384
+	case SHA512_256:
+		return sha512.Size256
+	default:
+		return 0
+	}
+}
+
+// New returns a new hash.Hash for this algorithm.
+// It panics if the algorithm is unknown.
+func (a HashAlgorithm) New() hash.Hash {
+	switch a {
+	case SHA256:
+		return sha256.New()
+	case SHA384:
+		return sha512.New384()
+	case SHA512_256:
+		return sha512.New512_256()
+	default:
+		panic(fmt.Sprintf("unknown hash algorithm %d", uint8(a)))
+	}
+}
+
+// HashLeaf returns the Merkle tree leaf hash of the provided data, as defined
+// by RFC 6962 but using this hash algorithm.
+func (a HashAlgorithm) HashLeaf(data []byte) []byte {
+	h := a.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// HashChildren returns the Merkle tree interior node hash of the provided
+// child hashes, as defined by RFC 6962 but using this hash algorithm.
+func (a HashAlgorithm) HashChildren(l, r []byte) []byte {
+	h := a.New()
+	h.Write([]byte{0x01})
+	h.Write(l)
+	h.Write(r)
+	return h.Sum(nil)
+}
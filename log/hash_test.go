@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+func TestHashAlgorithm(t *testing.T) {
+	for _, test := range []struct {
+		alg      log.HashAlgorithm
+		name     string
+		wantSize int
+	}{
+		{alg: log.SHA256, name: "sha256", wantSize: 32},
+		{alg: log.SHA384, name: "sha384", wantSize: 48},
+		{alg: log.SHA512_256, name: "sha512_256", wantSize: 32},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.alg.String(); got != test.name {
+				t.Errorf("String() = %q, want %q", got, test.name)
+			}
+			if got, err := log.ParseHashAlgorithm(test.name); err != nil || got != test.alg {
+				t.Errorf("ParseHashAlgorithm(%q) = %v, %v, want %v", test.name, got, err, test.alg)
+			}
+			if got := test.alg.Size(); got != test.wantSize {
+				t.Errorf("Size() = %d, want %d", got, test.wantSize)
+			}
+			if got := len(test.alg.HashLeaf([]byte("leaf"))); got != test.wantSize {
+				t.Errorf("len(HashLeaf()) = %d, want %d", got, test.wantSize)
+			}
+			if got := len(test.alg.HashChildren([]byte("l"), []byte("r"))); got != test.wantSize {
+				t.Errorf("len(HashChildren()) = %d, want %d", got, test.wantSize)
+			}
+		})
+	}
+
+	if _, err := log.ParseHashAlgorithm("md5"); err == nil {
+		t.Error("ParseHashAlgorithm(md5) succeeded")
+	}
+	if got := log.HashAlgorithm(99).Size(); got != 0 {
+		t.Errorf("Size() of unknown algorithm = %d, want 0", got)
+	}
+}
+
+func TestHashAlgorithm_DefaultIsRFC6962(t *testing.T) {
+	var alg log.HashAlgorithm
+	l, r := tlog.RecordHash([]byte("left")), tlog.RecordHash([]byte("right"))
+	if got := alg.HashLeaf([]byte("left")); !bytes.Equal(got, l[:]) {
+		t.Errorf("HashLeaf = %x, want %x", got, l)
+	}
+	if got, want := alg.HashChildren(l[:], r[:]), tlog.NodeHash(l, r); !bytes.Equal(got, want[:]) {
+		t.Errorf("HashChildren = %x, want %x", got, want)
+	}
+}
+
+func TestUnmarshalStrictWithHash(t *testing.T) {
+	h384 := base64.StdEncoding.EncodeToString(log.SHA384.HashLeaf([]byte("root")))
+	h256 := base64.StdEncoding.EncodeToString(log.SHA256.HashLeaf([]byte("root")))
+	var cp log.Checkpoint
+	if _, err := cp.UnmarshalStrictWithHash([]byte("example.com/log\n1\n"+h384+"\n"), log.SHA384); err != nil {
+		t.Errorf("UnmarshalStrictWithHash(SHA384) = %v", err)
+	}
+	if _, err := cp.UnmarshalStrictWithHash([]byte("example.com/log\n1\n"+h256+"\n"), log.SHA384); !errors.Is(err, log.ErrInvalidHashSize) {
+		t.Errorf("UnmarshalStrictWithHash(SHA384) = %v, want %v", err, log.ErrInvalidHashSize)
+	}
+	if _, err := cp.UnmarshalStrict([]byte("example.com/log\n1\n" + h384 + "\n")); !errors.Is(err, log.ErrInvalidHashSize) {
+		t.Errorf("UnmarshalStrict = %v, want %v", err, log.ErrInvalidHashSize)
+	}
+	if _, err := cp.UnmarshalStrictWithHash([]byte("example.com/log\n1\n"+h256+"\n"), log.HashAlgorithm(99)); err == nil {
+		t.Error("UnmarshalStrictWithHash with unknown algorithm succeeded")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"golang.org/x/mod/sumdb/note"
+)
+
+var (
+	// ErrUnknownOrigin is returned by Keyring.Parse when the checkpoint is for
+	// a log which has not been added to the keyring.
+	ErrUnknownOrigin = errors.New("unknown checkpoint origin")
+	// ErrWitnessPolicyNotSatisfied is returned by Keyring.Parse when the
+	// checkpoint is not cosigned by enough witnesses to satisfy the log's
+	// witness policy.
+	ErrWitnessPolicyNotSatisfied = errors.New("witness policy not satisfied")
+)
+
+// WitnessPolicy decides whether a checkpoint carries enough witness
+// cosignatures to be trusted. It is implemented by witness.Group.
+type WitnessPolicy interface {
+	// Satisfied returns true if the signed checkpoint note satisfies the policy.
+	Satisfied(cp []byte) bool
+}
+
+// Keyring holds the verifiers and witness policies for a set of logs, allowing
+// checkpoints from any of them to be verified without knowing in advance which
+// log they were issued by.
+//
+// A Keyring is safe for concurrent use.
+type Keyring struct {
+	mu   sync.RWMutex
+	logs map[string]keyringEntry
+}
+
+type keyringEntry struct {
+	verifiers []note.Verifier
+	policy    WitnessPolicy
+}
+
+// NewKeyring returns an empty Keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{logs: make(map[string]keyringEntry)}
+}
+
+// Add adds the log with the given origin to the keyring.
+//
+// Checkpoints from the log must be signed by at least one of the provided
+// verifiers; more than one may be given to allow for key rotation. If policy
+// is not nil, checkpoints must also satisfy it.
+//
+// Adding an origin which is already present is an error.
+func (k *Keyring) Add(origin string, policy WitnessPolicy, verifiers ...note.Verifier) error {
+	if origin == "" {
+		return errors.New("empty origin")
+	}
+	if len(verifiers) == 0 {
+		return fmt.Errorf("no verifiers for log %q", origin)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.logs[origin]; ok {
+		return fmt.Errorf("log %q already in keyring", origin)
+	}
+	k.logs[origin] = keyringEntry{verifiers: slices.Clone(verifiers), policy: policy}
+	return nil
+}
+
+// Verifiers returns the log verifiers for the given origin, or nil if the
+// log is not in the keyring.
+//
+// This allows a Keyring to be used to configure the logs known to a witness.
+func (k *Keyring) Verifiers(origin string) []note.Verifier {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return slices.Clone(k.logs[origin].verifiers)
+}
+
+// Parse verifies a raw signed checkpoint from any of the logs in the keyring,
+// returning the parsed checkpoint, any otherData in the body, and the
+// underlying note.
+//
+// The log is selected using the origin line of the checkpoint, and an error
+// wrapping ErrUnknownOrigin is returned if it is not in the keyring. The
+// checkpoint must be signed by one of the log's verifiers, and satisfy the
+// log's witness policy if it has one.
+//
+// The signatures on the returned note include only those from the log.
+func (k *Keyring) Parse(raw []byte) (*Checkpoint, []byte, *note.Note, error) {
+	origin, _, _ := bytes.Cut(raw, []byte("\n"))
+	k.mu.RLock()
+	e, ok := k.logs[string(origin)]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%w: %q", ErrUnknownOrigin, origin)
+	}
+
+	n, err := note.Open(raw, note.VerifierList(e.verifiers...))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to verify signatures on checkpoint: %w", err)
+	}
+	cp := &Checkpoint{}
+	otherData, err := cp.Unmarshal([]byte(n.Text))
+	if err != nil {
+		return nil, nil, n, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+	if e.policy != nil && !e.policy.Satisfied(raw) {
+		return nil, nil, n, fmt.Errorf("checkpoint for %q: %w", cp.Origin, ErrWitnessPolicyNotSatisfied)
+	}
+	return cp, otherData, n, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// signedByPolicy is a WitnessPolicy satisfied by any note with a valid
+// signature from v.
+type signedByPolicy struct{ v note.Verifier }
+
+func (p signedByPolicy) Satisfied(cp []byte) bool {
+	_, err := note.Open(cp, note.VerifierList(p.v))
+	return err == nil
+}
+
+func TestKeyring(t *testing.T) {
+	verifier := func(vkey string) note.Verifier {
+		t.Helper()
+		v, err := note.NewVerifier(vkey)
+		if err != nil {
+			t.Fatalf("NewVerifier: %v", err)
+		}
+		return v
+	}
+	cosign := func(n []byte, skey string) []byte {
+		t.Helper()
+		s, err := note.NewSigner(skey)
+		if err != nil {
+			t.Fatalf("NewSigner: %v", err)
+		}
+		parsed, err := note.Open(n, note.VerifierList(verifier(logVK)))
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		r, err := note.Sign(parsed, s)
+		if err != nil {
+			t.Fatalf("Sign: %v", err)
+		}
+		return r
+	}
+
+	k := log.NewKeyring()
+	// "Rotated" accepts checkpoints signed by either the old or the new key.
+	if err := k.Add("Rotated", nil, verifier(logVK), verifier(known2VK)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := k.Add("Witnessed", signedByPolicy{verifier(known1VK)}, verifier(logVK)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := k.Add("Rotated", nil, verifier(logVK)); err == nil {
+		t.Error("Add of duplicate origin succeeded")
+	}
+
+	rotated := log.Checkpoint{Origin: "Rotated", Size: 1, Hash: []byte("root")}
+	witnessed := log.Checkpoint{Origin: "Witnessed", Size: 2, Hash: []byte("root")}
+	for _, test := range []struct {
+		desc    string
+		raw     []byte
+		want    log.Checkpoint
+		wantErr bool
+		wantIs  error
+	}{
+		{
+			desc: "old key",
+			raw:  signCheckpoint(t, rotated, logSK),
+			want: rotated,
+		}, {
+			desc: "new key",
+			raw:  signCheckpoint(t, rotated, known2SK),
+			want: rotated,
+		}, {
+			desc:    "unknown key",
+			raw:     signCheckpoint(t, rotated, unknownSK),
+			wantErr: true,
+		}, {
+			desc:    "unknown origin",
+			raw:     signCheckpoint(t, log.Checkpoint{Origin: "Other", Size: 1, Hash: []byte("root")}, logSK),
+			wantErr: true,
+			wantIs:  log.ErrUnknownOrigin,
+		}, {
+			desc: "witness policy satisfied",
+			raw:  cosign(signCheckpoint(t, witnessed, logSK), known1SK),
+			want: witnessed,
+		}, {
+			desc:    "witness policy not satisfied",
+			raw:     signCheckpoint(t, witnessed, logSK),
+			wantErr: true,
+			wantIs:  log.ErrWitnessPolicyNotSatisfied,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			cp, _, _, err := k.Parse(test.raw)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("Parse = %v, wantErr %t", err, test.wantErr)
+			}
+			if test.wantIs != nil && !errors.Is(err, test.wantIs) {
+				t.Errorf("Parse = %v, want %v", err, test.wantIs)
+			}
+			if err != nil {
+				return
+			}
+			if cp.Origin != test.want.Origin || cp.Size != test.want.Size {
+				t.Errorf("Parse = %+v, want %+v", cp, test.want)
+			}
+		})
+	}
+
+	if got := k.Verifiers("Rotated"); len(got) != 2 {
+		t.Errorf("Verifiers(Rotated) returned %d verifiers, want 2", len(got))
+	}
+	if got := k.Verifiers("Other"); got != nil {
+		t.Errorf("Verifiers(Other) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Errors returned when verifying Merkle tree proofs.
+var (
+	// ErrMalformedProof is returned for a proof which cannot be valid for the
+	// tree sizes involved, e.g. because it has the wrong number of hashes.
+	ErrMalformedProof = errors.New("malformed proof")
+	// ErrRootMismatch is returned when a proof does not verify against the
+	// expected root hash.
+	ErrRootMismatch = errors.New("root hash mismatch")
+)
+
+// RootFromInclusionProof calculates the root hash of a tree of the given size
+// from the leaf hash at index and its inclusion proof, using the algorithm in
+// https://www.rfc-editor.org/rfc/rfc9162.html#section-2.1.3.2.
+func RootFromInclusionProof(alg HashAlgorithm, index, size uint64, leafHash []byte, proof [][]byte) ([]byte, error) {
+	if err := checkHashSizes(alg, append([][]byte{leafHash}, proof...)); err != nil {
+		return nil, err
+	}
+	if index >= size {
+		return nil, fmt.Errorf("%w: index %d out of range for tree of size %d", ErrMalformedProof, index, size)
+	}
+	fn, sn := index, size-1
+	r := leafHash
+	for _, p := range proof {
+		if sn == 0 {
+			return nil, fmt.Errorf("%w: inclusion proof too long", ErrMalformedProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			r = alg.HashChildren(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = alg.HashChildren(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return nil, fmt.Errorf("%w: inclusion proof too short", ErrMalformedProof)
+	}
+	return r, nil
+}
+
+// VerifyInclusion checks that the inclusion proof shows the leaf hash to be
+// at index in the tree of the given size with the provided root hash.
+func VerifyInclusion(alg HashAlgorithm, index, size uint64, leafHash []byte, proof [][]byte, root []byte) error {
+	r, err := RootFromInclusionProof(alg, index, size, leafHash, proof)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(r, root) {
+		return fmt.Errorf("%w: calculated root %x does not match expected root %x", ErrRootMismatch, r, root)
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the consistency proof shows the tree of size1
+// with root1 to be a prefix of the tree of size2 with root2, using the
+// algorithm in https://www.rfc-editor.org/rfc/rfc9162.html#section-2.1.4.2.
+func VerifyConsistency(alg HashAlgorithm, size1, size2 uint64, proof [][]byte, root1, root2 []byte) error {
+	if err := checkHashSizes(alg, append([][]byte{root1, root2}, proof...)); err != nil {
+		return err
+	}
+	switch {
+	case size1 > size2:
+		return fmt.Errorf("%w: tree size %d is larger than tree size %d", ErrMalformedProof, size1, size2)
+	case size1 == size2:
+		if len(proof) > 0 {
+			return fmt.Errorf("%w: consistency proof for trees of the same size must be empty", ErrMalformedProof)
+		}
+		if !bytes.Equal(root1, root2) {
+			return fmt.Errorf("%w: roots %x and %x differ for trees of the same size", ErrRootMismatch, root1, root2)
+		}
+		return nil
+	case size1 == 0:
+		if len(proof) > 0 {
+			return fmt.Errorf("%w: consistency proof from an empty tree must be empty", ErrMalformedProof)
+		}
+		return nil
+	case len(proof) == 0:
+		return fmt.Errorf("%w: empty consistency proof", ErrMalformedProof)
+	}
+
+	// If size1 is a power of two, the old root is the first node of the proof.
+	if size1&(size1-1) == 0 {
+		proof = append([][]byte{root1}, proof...)
+	}
+	fr, sr, err := rootsFromConsistencyProof(alg, size1, size2, proof)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(fr, root1) {
+		return fmt.Errorf("%w: calculated root %x does not match expected root %x for tree size %d", ErrRootMismatch, fr, root1, size1)
+	}
+	if !bytes.Equal(sr, root2) {
+		return fmt.Errorf("%w: calculated root %x does not match expected root %x for tree size %d", ErrRootMismatch, sr, root2, size2)
+	}
+	return nil
+}
+
+// rootsFromConsistencyProof calculates the root hashes of the trees of size1
+// and size2, where 0 < size1 < size2, from a non-empty consistency proof.
+//
+// Where size1 is a power of two, the proof must begin with the root hash of
+// the tree of size1, which RFC 9162 omits from such proofs.
+func rootsFromConsistencyProof(alg HashAlgorithm, size1, size2 uint64, proof [][]byte) ([]byte, []byte, error) {
+	fn, sn := size1-1, size2-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return nil, nil, fmt.Errorf("%w: consistency proof too long", ErrMalformedProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = alg.HashChildren(c, fr)
+			sr = alg.HashChildren(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = alg.HashChildren(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return nil, nil, fmt.Errorf("%w: consistency proof too short", ErrMalformedProof)
+	}
+	return fr, sr, nil
+}
+
+// ValidSubtree returns true if [start, end) is a valid subtree of a log, as
+// defined for Merkle Tree Certificates: it must be non-empty, and start must be
+// a multiple of the smallest power of two which is at least end-start. Such a
+// subtree is either a node of every tree containing it, or the left part of
+// one.
+func ValidSubtree(start, end uint64) bool {
+	if start >= end {
+		return false
+	}
+	s := uint64(1) << bits.Len64(end-start-1)
+	return start%s == 0
+}
+
+// VerifySubtreeInclusion checks that the inclusion proof shows the leaf hash
+// to be at index in the subtree [start, end) with the provided hash.
+//
+// The proof is an inclusion proof of the leaf at index-start in the tree made
+// up of the entries of the subtree.
+func VerifySubtreeInclusion(alg HashAlgorithm, start, end, index uint64, leafHash []byte, proof [][]byte, subtreeHash []byte) error {
+	if !ValidSubtree(start, end) {
+		return fmt.Errorf("%w: invalid subtree [%d, %d)", ErrMalformedProof, start, end)
+	}
+	if index < start || index >= end {
+		return fmt.Errorf("%w: index %d out of range for subtree [%d, %d)", ErrMalformedProof, index, start, end)
+	}
+	return VerifyInclusion(alg, index-start, end-start, leafHash, proof, subtreeHash)
+}
+
+// VerifySubtreeConsistency checks that the consistency proof shows the subtree
+// [start, end) with subtreeHash to be part of the tree of the given size with
+// the provided root hash.
+//
+// The proof is as defined for Merkle Tree Certificates, which generalises the
+// consistency proofs of RFC 9162: where start is zero, the two are the same.
+func VerifySubtreeConsistency(alg HashAlgorithm, start, end, size uint64, proof [][]byte, subtreeHash, root []byte) error {
+	if err := checkHashSizes(alg, append([][]byte{subtreeHash, root}, proof...)); err != nil {
+		return err
+	}
+	if !ValidSubtree(start, end) {
+		return fmt.Errorf("%w: invalid subtree [%d, %d)", ErrMalformedProof, start, end)
+	}
+	if end > size {
+		return fmt.Errorf("%w: subtree [%d, %d) is not in tree of size %d", ErrMalformedProof, start, end, size)
+	}
+
+	// Descend from the root of the tree towards the subtree, as the proof was
+	// built, recording the side of the tree the subtree is in at each level.
+	// Each level contributes the hash of the other side to the end of the
+	// proof.
+	const (
+		left = iota
+		right
+		// across is where the subtree starts at the beginning of the tree
+		// and extends into its right side, as in RFC 9162.
+		across
+	)
+	var steps []int
+	known := true
+	for start != 0 || end != size {
+		k := uint64(1) << (bits.Len64(size-1) - 1)
+		switch {
+		case end <= k:
+			steps = append(steps, left)
+			size = k
+		case start >= k:
+			steps = append(steps, right)
+			start, end, size = start-k, end-k, size-k
+		default:
+			// The subtree is valid, so this is only reached with a start of
+			// zero. The hash of the remaining part of the subtree is not known
+			// to the verifier, so is included in the proof.
+			steps = append(steps, across)
+			end, size = end-k, size-k
+			known = false
+		}
+	}
+	want := len(steps)
+	if !known {
+		want++
+	}
+	if len(proof) != want {
+		return fmt.Errorf("%w: subtree consistency proof has %d hashes, want %d", ErrMalformedProof, len(proof), want)
+	}
+
+	// Then ascend from the subtree, calculating the hash of both the subtree
+	// and the tree.
+	sr, r := subtreeHash, subtreeHash
+	if !known {
+		sr, r = proof[0], proof[0]
+		proof = proof[1:]
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		c := proof[len(steps)-1-i]
+		switch steps[i] {
+		case left:
+			r = alg.HashChildren(r, c)
+		case right:
+			r = alg.HashChildren(c, r)
+		case across:
+			sr = alg.HashChildren(c, sr)
+			r = alg.HashChildren(c, r)
+		}
+	}
+	if !bytes.Equal(sr, subtreeHash) {
+		return fmt.Errorf("%w: calculated subtree hash %x does not match expected hash %x", ErrRootMismatch, sr, subtreeHash)
+	}
+	if !bytes.Equal(r, root) {
+		return fmt.Errorf("%w: calculated root %x does not match expected root %x", ErrRootMismatch, r, root)
+	}
+	return nil
+}
+
+// checkHashSizes returns an error if any of the provided hashes is not the
+// size of those produced by alg.
+func checkHashSizes(alg HashAlgorithm, hashes [][]byte) error {
+	if alg.Size() == 0 {
+		return fmt.Errorf("%w %v", ErrUnknownHashAlgorithm, alg)
+	}
+	for _, h := range hashes {
+		if len(h) != alg.Size() {
+			return fmt.Errorf("%w: hash length was %d, expected %d for %v", ErrMalformedProof, len(h), alg.Size(), alg)
+		}
+	}
+	return nil
+}
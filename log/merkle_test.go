@@ -0,0 +1,286 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/transparency-dev/formats/internal/merkletest"
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+func TestVerifyInclusion(t *testing.T) {
+	for _, alg := range []log.HashAlgorithm{log.SHA256, log.SHA384, log.SHA512_256} {
+		t.Run(alg.String(), func(t *testing.T) {
+			leaves := merkletest.Leaves(33)
+			for size := 1; size <= len(leaves); size++ {
+				root := merkletest.Root(alg, leaves[:size])
+				for i := range size {
+					proof := merkletest.Inclusion(alg, i, leaves[:size])
+					leafHash := alg.HashLeaf(leaves[i])
+					if err := log.VerifyInclusion(alg, uint64(i), uint64(size), leafHash, proof, root); err != nil {
+						t.Fatalf("log.VerifyInclusion(%d, %d): %v", i, size, err)
+					}
+					if err := log.VerifyInclusion(alg, uint64(i), uint64(size), alg.HashLeaf([]byte("nope")), proof, root); err == nil {
+						t.Fatalf("log.VerifyInclusion(%d, %d) succeeded with wrong leaf", i, size)
+					}
+					if size > 1 {
+						if err := log.VerifyInclusion(alg, uint64(i), uint64(size), leafHash, proof[1:], root); err == nil {
+							t.Fatalf("log.VerifyInclusion(%d, %d) succeeded with short proof", i, size)
+						}
+					}
+					if err := log.VerifyInclusion(alg, uint64(i), uint64(size), leafHash, append(proof, root), root); err == nil {
+						t.Fatalf("log.VerifyInclusion(%d, %d) succeeded with long proof", i, size)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyInclusion_Errors(t *testing.T) {
+	leaf := log.SHA256.HashLeaf([]byte("leaf"))
+	for _, test := range []struct {
+		desc  string
+		alg   log.HashAlgorithm
+		index uint64
+		size  uint64
+		leaf  []byte
+		proof [][]byte
+	}{
+		{desc: "unknown algorithm", alg: log.HashAlgorithm(99), size: 1, leaf: leaf},
+		{desc: "wrong leaf size", alg: log.SHA384, size: 1, leaf: leaf},
+		{desc: "wrong proof hash size", alg: log.SHA256, size: 2, leaf: leaf, proof: [][]byte{[]byte("short")}},
+		{desc: "index out of range", alg: log.SHA256, index: 1, size: 1, leaf: leaf},
+		{desc: "empty tree", alg: log.SHA256, leaf: leaf},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if err := log.VerifyInclusion(test.alg, test.index, test.size, test.leaf, test.proof, test.leaf); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestVerifyInclusion_MatchesTLog(t *testing.T) {
+	leaves := merkletest.Leaves(20)
+	var hashes []tlog.Hash
+	r := tlog.HashReaderFunc(func(idx []int64) ([]tlog.Hash, error) {
+		res := make([]tlog.Hash, len(idx))
+		for i, x := range idx {
+			res[i] = hashes[x]
+		}
+		return res, nil
+	})
+	for i, l := range leaves {
+		hs, err := tlog.StoredHashes(int64(i), l, r)
+		if err != nil {
+			t.Fatalf("StoredHashes: %v", err)
+		}
+		hashes = append(hashes, hs...)
+	}
+	size := int64(len(leaves))
+	root, err := tlog.TreeHash(size, r)
+	if err != nil {
+		t.Fatalf("TreeHash: %v", err)
+	}
+	for i := range size {
+		p, err := tlog.ProveRecord(size, i, r)
+		if err != nil {
+			t.Fatalf("ProveRecord: %v", err)
+		}
+		proof := make([][]byte, len(p))
+		for j := range p {
+			proof[j] = p[j][:]
+		}
+		leafHash := tlog.RecordHash(leaves[i])
+		if err := log.VerifyInclusion(log.SHA256, uint64(i), uint64(size), leafHash[:], proof, root[:]); err != nil {
+			t.Errorf("log.VerifyInclusion(%d): %v", i, err)
+		}
+	}
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	for _, alg := range []log.HashAlgorithm{log.SHA256, log.SHA384, log.SHA512_256} {
+		t.Run(alg.String(), func(t *testing.T) {
+			leaves := merkletest.Leaves(33)
+			for size2 := 1; size2 <= len(leaves); size2++ {
+				root2 := merkletest.Root(alg, leaves[:size2])
+				for size1 := 1; size1 <= size2; size1++ {
+					root1 := merkletest.Root(alg, leaves[:size1])
+					proof := merkletest.Consistency(alg, size1, leaves[:size2], true)
+					if err := log.VerifyConsistency(alg, uint64(size1), uint64(size2), proof, root1, root2); err != nil {
+						t.Fatalf("log.VerifyConsistency(%d, %d): %v", size1, size2, err)
+					}
+					if size1 == size2 {
+						continue
+					}
+					if err := log.VerifyConsistency(alg, uint64(size1), uint64(size2), proof, root2, root2); err == nil {
+						t.Fatalf("log.VerifyConsistency(%d, %d) succeeded with wrong old root", size1, size2)
+					}
+					if err := log.VerifyConsistency(alg, uint64(size1), uint64(size2), proof, root1, root1); err == nil {
+						t.Fatalf("log.VerifyConsistency(%d, %d) succeeded with wrong new root", size1, size2)
+					}
+					if err := log.VerifyConsistency(alg, uint64(size1), uint64(size2), proof[1:], root1, root2); err == nil {
+						t.Fatalf("log.VerifyConsistency(%d, %d) succeeded with short proof", size1, size2)
+					}
+					if err := log.VerifyConsistency(alg, uint64(size1), uint64(size2), append(proof, root1), root1, root2); err == nil {
+						t.Fatalf("log.VerifyConsistency(%d, %d) succeeded with long proof", size1, size2)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyConsistency_EdgeCases(t *testing.T) {
+	alg := log.SHA256
+	r1 := alg.HashLeaf([]byte("one"))
+	r2 := alg.HashLeaf([]byte("two"))
+	for _, test := range []struct {
+		desc         string
+		size1, size2 uint64
+		proof        [][]byte
+		root1, root2 []byte
+		wantErr      bool
+	}{
+		{desc: "from empty tree", size1: 0, size2: 10, root1: r1, root2: r2},
+		{desc: "from empty tree with proof", size1: 0, size2: 10, proof: [][]byte{r1}, root1: r1, root2: r2, wantErr: true},
+		{desc: "same size and root", size1: 10, size2: 10, root1: r1, root2: r1},
+		{desc: "same size different root", size1: 10, size2: 10, root1: r1, root2: r2, wantErr: true},
+		{desc: "same size with proof", size1: 10, size2: 10, proof: [][]byte{r1}, root1: r1, root2: r1, wantErr: true},
+		{desc: "shrinking tree", size1: 11, size2: 10, root1: r1, root2: r2, wantErr: true},
+		{desc: "empty proof", size1: 3, size2: 10, root1: r1, root2: r2, wantErr: true},
+		{desc: "wrong root size", size1: 3, size2: 10, root1: r1[1:], root2: r2, wantErr: true},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			err := log.VerifyConsistency(alg, test.size1, test.size2, test.proof, test.root1, test.root2)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("VerifyConsistency = %v, wantErr %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidSubtree(t *testing.T) {
+	for _, test := range []struct {
+		start, end uint64
+		want       bool
+	}{
+		{start: 0, end: 1, want: true},
+		{start: 0, end: 7, want: true},
+		{start: 5, end: 6, want: true},
+		{start: 4, end: 8, want: true},
+		{start: 8, end: 13, want: true},
+		{start: 8, end: 17},
+		{start: 4, end: 9},
+		{start: 2, end: 5},
+		{start: 3, end: 3},
+		{start: 4, end: 3},
+	} {
+		if got := log.ValidSubtree(test.start, test.end); got != test.want {
+			t.Errorf("ValidSubtree(%d, %d) = %t, want %t", test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestVerifySubtreeInclusion(t *testing.T) {
+	alg := log.SHA256
+	leaves := merkletest.Leaves(16)
+	start, end := 8, 14
+	subtreeHash := merkletest.Root(alg, leaves[start:end])
+	for i := start; i < end; i++ {
+		proof := merkletest.Inclusion(alg, i-start, leaves[start:end])
+		if err := log.VerifySubtreeInclusion(alg, uint64(start), uint64(end), uint64(i), alg.HashLeaf(leaves[i]), proof, subtreeHash); err != nil {
+			t.Errorf("VerifySubtreeInclusion(%d): %v", i, err)
+		}
+		if err := log.VerifySubtreeInclusion(alg, uint64(start), uint64(end), uint64(i), alg.HashLeaf(leaves[0]), proof, subtreeHash); err == nil {
+			t.Errorf("VerifySubtreeInclusion(%d) succeeded with wrong leaf", i)
+		}
+	}
+	if err := log.VerifySubtreeInclusion(alg, 8, 14, 14, alg.HashLeaf(leaves[14]), nil, subtreeHash); err == nil {
+		t.Error("VerifySubtreeInclusion succeeded for index outside subtree")
+	}
+	if err := log.VerifySubtreeInclusion(alg, 6, 10, 8, alg.HashLeaf(leaves[8]), nil, subtreeHash); err == nil {
+		t.Error("VerifySubtreeInclusion succeeded for invalid subtree")
+	}
+}
+
+func TestVerifySubtreeConsistency(t *testing.T) {
+	for _, alg := range []log.HashAlgorithm{log.SHA256, log.SHA384} {
+		t.Run(alg.String(), func(t *testing.T) {
+			leaves := merkletest.Leaves(33)
+			for size := 1; size <= len(leaves); size++ {
+				root := merkletest.Root(alg, leaves[:size])
+				for end := 1; end <= size; end++ {
+					for start := range end {
+						if !log.ValidSubtree(uint64(start), uint64(end)) {
+							continue
+						}
+						subtreeHash := merkletest.Root(alg, leaves[start:end])
+						proof := merkletest.SubtreeConsistency(alg, start, end, leaves[:size], true)
+						if start == 0 {
+							if want := merkletest.Consistency(alg, end, leaves[:size], true); fmt.Sprint(proof) != fmt.Sprint(want) {
+								t.Fatalf("subtree consistency proof for [0, %d) differs from consistency proof", end)
+							}
+						}
+						if err := log.VerifySubtreeConsistency(alg, uint64(start), uint64(end), uint64(size), proof, subtreeHash, root); err != nil {
+							t.Fatalf("VerifySubtreeConsistency(%d, %d, %d): %v", start, end, size, err)
+						}
+						if err := log.VerifySubtreeConsistency(alg, uint64(start), uint64(end), uint64(size), proof, alg.HashLeaf([]byte("nope")), root); err == nil {
+							t.Fatalf("VerifySubtreeConsistency(%d, %d, %d) succeeded with wrong subtree hash", start, end, size)
+						}
+						if end == size && start == 0 {
+							continue
+						}
+						if err := log.VerifySubtreeConsistency(alg, uint64(start), uint64(end), uint64(size), proof, subtreeHash, subtreeHash); err == nil {
+							t.Fatalf("VerifySubtreeConsistency(%d, %d, %d) succeeded with wrong root", start, end, size)
+						}
+						if err := log.VerifySubtreeConsistency(alg, uint64(start), uint64(end), uint64(size), proof[1:], subtreeHash, root); err == nil {
+							t.Fatalf("VerifySubtreeConsistency(%d, %d, %d) succeeded with short proof", start, end, size)
+						}
+						if err := log.VerifySubtreeConsistency(alg, uint64(start), uint64(end), uint64(size), append(proof, root), subtreeHash, root); err == nil {
+							t.Fatalf("VerifySubtreeConsistency(%d, %d, %d) succeeded with long proof", start, end, size)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVerifySubtreeConsistency_Errors(t *testing.T) {
+	alg := log.SHA256
+	h := alg.HashLeaf([]byte("one"))
+	for _, test := range []struct {
+		desc             string
+		start, end, size uint64
+		proof            [][]byte
+		subtree, root    []byte
+	}{
+		{desc: "invalid subtree", start: 2, end: 5, size: 8, proof: [][]byte{h, h}, subtree: h, root: h},
+		{desc: "subtree beyond tree", start: 8, end: 12, size: 10, proof: [][]byte{h}, subtree: h, root: h},
+		{desc: "wrong hash size", start: 0, end: 4, size: 8, proof: [][]byte{h[1:]}, subtree: h, root: h},
+		{desc: "same tree different hash", start: 0, end: 8, size: 8, subtree: h, root: alg.HashLeaf([]byte("two"))},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if err := log.VerifySubtreeConsistency(alg, test.start, test.end, test.size, test.proof, test.subtree, test.root); err == nil {
+				t.Error("VerifySubtreeConsistency succeeded, want error")
+			}
+		})
+	}
+}
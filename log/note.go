@@ -15,11 +15,29 @@
 package log
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"golang.org/x/mod/sumdb/note"
 )
 
+// Errors returned by ParseCheckpointStrict.
+var (
+	// ErrDuplicateLogSignature is returned when a checkpoint carries more than
+	// one signature from the log's key.
+	ErrDuplicateLogSignature = errors.New("invalid checkpoint - more than one log signature")
+	// ErrKeyHashCollision is returned when two of the provided verifiers have
+	// the same name and key hash, and so signatures from them cannot be told
+	// apart.
+	ErrKeyHashCollision = errors.New("verifiers have colliding name and key hash")
+	// ErrDuplicateWitnessSignature is returned when a checkpoint carries more
+	// than one signature from the same one of the other verifiers.
+	ErrDuplicateWitnessSignature = errors.New("invalid checkpoint - more than one signature from a witness")
+)
+
 // ParseCheckpoint takes a raw checkpoint as bytes and returns a parsed checkpoint
 // and any otherData in the body, providing that:
 // * a valid log signature is found; and
@@ -29,13 +47,23 @@ import (
 // returned where possible.
 // The signatures on the note will include the log signature if no error is returned,
 // plus any signatures from otherVerifiers that were found.
+// To restrict a rotated log key to the checkpoints it was in service for, wrap
+// logVerifier with NewVerifierWithValidity from the
+// github.com/transparency-dev/formats/note package.
+//
+// Errors from note.Open, such as *note.UnverifiedNoteError when no signature
+// is from a known key and *note.InvalidSignatureError for a bad signature, are
+// wrapped so that they can be inspected with errors.As. Otherwise, a
+// malformed body results in a *CheckpointError, an unexpected origin in an
+// *OriginMismatchError, and a missing log signature in a *SignatureError
+// wrapping ErrNoLogSignature.
 func ParseCheckpoint(chkpt []byte, origin string, logVerifier note.Verifier, otherVerifiers ...note.Verifier) (*Checkpoint, []byte, *note.Note, error) {
 	vs := append(append(make([]note.Verifier, 0, len(otherVerifiers)+1), logVerifier), otherVerifiers...)
 	verifiers := note.VerifierList(vs...)
 
 	n, err := note.Open(chkpt, verifiers)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to verify signatures on checkpoint: %v", err)
+		return nil, nil, nil, fmt.Errorf("failed to verify signatures on checkpoint: %w", err)
 	}
 
 	for _, s := range n.Sigs {
@@ -44,13 +72,95 @@ func ParseCheckpoint(chkpt []byte, origin string, logVerifier note.Verifier, oth
 			cp := &Checkpoint{}
 			var otherData []byte
 			if otherData, err = cp.Unmarshal([]byte(n.Text)); err != nil {
-				return nil, nil, n, fmt.Errorf("failed to unmarshal checkpoint: %v", err)
+				return nil, nil, n, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
 			}
 			if cp.Origin != origin {
-				return nil, nil, n, fmt.Errorf("got Origin %q but expected %q", cp.Origin, origin)
+				return nil, nil, n, &OriginMismatchError{Got: cp.Origin, Want: origin}
 			}
 			return cp, otherData, n, nil
 		}
 	}
-	return nil, nil, n, fmt.Errorf("no log signature found on note")
+	return nil, nil, n, &SignatureError{Name: logVerifier.Name(), KeyHash: logVerifier.KeyHash(), Err: ErrNoLogSignature}
+}
+
+// ParseCheckpointStrict is like ParseCheckpoint, but additionally:
+// * the checkpoint body must pass Checkpoint.UnmarshalStrict; and
+// * no two of logVerifier and otherVerifiers may have the same name and key
+// hash, otherwise ErrKeyHashCollision is returned; and
+// * the checkpoint must not carry more than one signature line from the log's
+// key, otherwise ErrDuplicateLogSignature is returned; and
+// * the checkpoint must not carry more than one signature line from any of
+// otherVerifiers, otherwise ErrDuplicateWitnessSignature is returned.
+//
+// ParseCheckpoint, following note.Open, silently ignores all but the first
+// signature line from a key, so a checkpoint with a duplicate signature line
+// may still be accepted by it.
+func ParseCheckpointStrict(chkpt []byte, origin string, logVerifier note.Verifier, otherVerifiers ...note.Verifier) (*Checkpoint, []byte, *note.Note, error) {
+	type nameHash struct {
+		name string
+		hash uint32
+	}
+	logKey := nameHash{logVerifier.Name(), logVerifier.KeyHash()}
+	known := map[nameHash]bool{logKey: true}
+	for _, v := range otherVerifiers {
+		k := nameHash{v.Name(), v.KeyHash()}
+		if known[k] {
+			return nil, nil, nil, &SignatureError{Name: k.name, KeyHash: k.hash, Err: ErrKeyHashCollision}
+		}
+		known[k] = true
+	}
+
+	seen := make(map[nameHash]bool)
+	for _, l := range signatureLines(chkpt) {
+		name, hash, ok := parseSignatureLine(l)
+		if !ok {
+			// Let note.Open report the malformed note.
+			break
+		}
+		k := nameHash{name, hash}
+		if !known[k] {
+			continue
+		}
+		if seen[k] {
+			if k == logKey {
+				return nil, nil, nil, &SignatureError{Name: name, KeyHash: hash, Err: ErrDuplicateLogSignature}
+			}
+			return nil, nil, nil, &SignatureError{Name: name, KeyHash: hash, Err: ErrDuplicateWitnessSignature}
+		}
+		seen[k] = true
+	}
+
+	cp, _, n, err := ParseCheckpoint(chkpt, origin, logVerifier, otherVerifiers...)
+	if err != nil {
+		return nil, nil, n, err
+	}
+	otherData, err := cp.UnmarshalStrict([]byte(n.Text))
+	if err != nil {
+		return nil, nil, n, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+	return cp, otherData, n, nil
+}
+
+// signatureLines returns the signature lines of a note, without their
+// trailing newlines.
+func signatureLines(n []byte) [][]byte {
+	i := bytes.LastIndex(n, []byte("\n\n"))
+	if i < 0 {
+		return nil
+	}
+	return bytes.Split(bytes.TrimSuffix(n[i+2:], []byte("\n")), []byte("\n"))
+}
+
+// parseSignatureLine returns the key name and hash from a note signature line.
+func parseSignatureLine(l []byte) (string, uint32, bool) {
+	rest, ok := bytes.CutPrefix(l, []byte(sigLinePrefix))
+	if !ok {
+		return "", 0, false
+	}
+	name, b64, _ := bytes.Cut(rest, []byte(" "))
+	sig, err := base64.StdEncoding.DecodeString(string(b64))
+	if err != nil || len(sig) < 4 {
+		return "", 0, false
+	}
+	return string(name), binary.BigEndian.Uint32(sig), true
 }
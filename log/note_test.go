@@ -15,7 +15,9 @@
 package log_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/transparency-dev/formats/log"
@@ -405,3 +407,74 @@ func makeIDs(t testing.TB, ns int, nv int) ([]note.Signer, []note.Verifier) {
 	}
 	return ss[:ns], vs[:nv]
 }
+
+func TestParseCheckpointStrict(t *testing.T) {
+	logVerifier, err := note.NewVerifier(logVK)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	known1Verifier, err := note.NewVerifier(known1VK)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	cp := log.Checkpoint{Origin: "TestParseCheckpointStrict", Size: 42, Hash: make([]byte, 32)}
+	sigLine := func(skey string) string {
+		t.Helper()
+		n := string(signCheckpoint(t, cp, skey))
+		return n[strings.LastIndex(n, "\n\n")+2:]
+	}
+	body := string(cp.Marshal()) + "\n"
+
+	for _, test := range []struct {
+		desc      string
+		chkpt     string
+		others    []note.Verifier
+		wantErrIs error
+	}{
+		{
+			desc:   "log and witness signatures",
+			chkpt:  body + sigLine(logSK) + sigLine(known1SK),
+			others: []note.Verifier{known1Verifier},
+		}, {
+			desc:      "duplicate log signature",
+			chkpt:     body + sigLine(logSK) + sigLine(known1SK) + sigLine(logSK),
+			others:    []note.Verifier{known1Verifier},
+			wantErrIs: log.ErrDuplicateLogSignature,
+		}, {
+			desc:      "duplicate witness signature",
+			chkpt:     body + sigLine(logSK) + sigLine(known1SK) + sigLine(known1SK),
+			others:    []note.Verifier{known1Verifier},
+			wantErrIs: log.ErrDuplicateWitnessSignature,
+		}, {
+			desc:  "duplicate unknown signature is ignored",
+			chkpt: body + sigLine(logSK) + sigLine(known1SK) + sigLine(known1SK),
+		}, {
+			desc:      "log verifier collides with other verifier",
+			chkpt:     body + sigLine(logSK),
+			others:    []note.Verifier{logVerifier},
+			wantErrIs: log.ErrKeyHashCollision,
+		}, {
+			desc:      "other verifiers collide",
+			chkpt:     body + sigLine(logSK),
+			others:    []note.Verifier{known1Verifier, known1Verifier},
+			wantErrIs: log.ErrKeyHashCollision,
+		}, {
+			desc:      "non-canonical body",
+			chkpt:     string(signCheckpoint(t, log.Checkpoint{Origin: cp.Origin, Size: 42, Hash: []byte("short")}, logSK)),
+			wantErrIs: log.ErrInvalidHashSize,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, _, _, err := log.ParseCheckpointStrict([]byte(test.chkpt), cp.Origin, logVerifier, test.others...)
+			if test.wantErrIs == nil {
+				if err != nil {
+					t.Fatalf("ParseCheckpointStrict: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, test.wantErrIs) {
+				t.Errorf("ParseCheckpointStrict = %v, want %v", err, test.wantErrIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Errors returned by Tracker.Update.
+var (
+	// ErrConsistencyProofRequired is returned when a checkpoint which grows a
+	// log is presented without a consistency proof.
+	ErrConsistencyProofRequired = errors.New("consistency proof required")
+	// ErrInvalidConsistencyProof is returned when a checkpoint which grows a
+	// log is presented with a consistency proof which does not verify. The
+	// error also wraps the error returned by VerifyConsistency.
+	ErrInvalidConsistencyProof = errors.New("invalid consistency proof")
+)
+
+// TrackedCheckpoint is the latest checkpoint known for a log.
+type TrackedCheckpoint struct {
+	Checkpoint Checkpoint
+	// Signed is the signed note from which Checkpoint was parsed, if known.
+	Signed []byte
+}
+
+// CheckpointStore persists the latest checkpoint known for each log, keyed by
+// the log ID as returned by ID.
+type CheckpointStore interface {
+	// Latest returns the latest checkpoint stored for the log, or nil if there
+	// is none.
+	Latest(id string) (*TrackedCheckpoint, error)
+	// SetLatest replaces the latest checkpoint stored for the log.
+	SetLatest(id string, tc TrackedCheckpoint) error
+}
+
+// EventKind describes the outcome of presenting a checkpoint to a Tracker.
+type EventKind int
+
+const (
+	// EventAdvanced means that the checkpoint is the first seen for the log,
+	// or that it has been proven to be consistent with, and larger than, the
+	// previous latest checkpoint. It is now the latest checkpoint.
+	EventAdvanced EventKind = iota + 1
+	// EventUnchanged means that the checkpoint commits to the same tree as
+	// the latest checkpoint.
+	EventUnchanged
+	// EventStale means that the checkpoint is smaller than the latest
+	// checkpoint.
+	EventStale
+	// EventEquivocation means that the checkpoint has the same size as the
+	// latest checkpoint, but a different root hash. The log has presented
+	// (at least) two different views of its contents.
+	EventEquivocation
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventAdvanced:
+		return "advanced"
+	case EventUnchanged:
+		return "unchanged"
+	case EventStale:
+		return "stale"
+	case EventEquivocation:
+		return "equivocation"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event describes the outcome of presenting a checkpoint to a Tracker.
+type Event struct {
+	Kind EventKind
+	// ID is the log ID of the checkpoint's origin.
+	ID string
+	// Previous is the latest checkpoint known before the update, or nil if
+	// there was none.
+	Previous *TrackedCheckpoint
+	// Current is the checkpoint presented to the tracker.
+	Current TrackedCheckpoint
+}
+
+// Tracker keeps a record of the latest verified checkpoint for each log, and
+// ensures that the record only ever moves forward in an append-only manner.
+type Tracker struct {
+	store CheckpointStore
+	alg   HashAlgorithm
+	mu    sync.Mutex
+}
+
+// NewTracker returns a Tracker which records checkpoints in the provided
+// store, and verifies consistency proofs using the given hash algorithm.
+func NewTracker(store CheckpointStore, alg HashAlgorithm) *Tracker {
+	return &Tracker{store: store, alg: alg}
+}
+
+// Update presents a checkpoint, along with the signed note it was parsed
+// from, to the tracker. The caller is responsible for having verified the
+// log's signature on the checkpoint, e.g. by using ParseCheckpoint.
+//
+// Checkpoints which are larger than the latest checkpoint for the log must be
+// accompanied by a consistency proof from the latest checkpoint, otherwise
+// ErrConsistencyProofRequired is returned. If the proof does not verify,
+// ErrInvalidConsistencyProof is returned. Proofs are ignored in all other
+// cases.
+//
+// The returned Event describes how the checkpoint relates to the latest
+// checkpoint. Only EventAdvanced updates the stored checkpoint.
+func (t *Tracker) Update(cp Checkpoint, signed []byte, consistency [][]byte) (Event, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := ID(cp.Origin)
+	prev, err := t.store.Latest(id)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to read latest checkpoint for %q: %w", cp.Origin, err)
+	}
+	e := Event{
+		ID:       id,
+		Previous: prev,
+		Current:  TrackedCheckpoint{Checkpoint: cp, Signed: signed},
+	}
+	switch {
+	case prev == nil:
+		// First sighting of this log, so there's nothing to be consistent with.
+	case cp.Size < prev.Checkpoint.Size:
+		e.Kind = EventStale
+		return e, nil
+	case cp.Size == prev.Checkpoint.Size:
+		e.Kind = EventUnchanged
+		if !bytes.Equal(cp.Hash, prev.Checkpoint.Hash) {
+			e.Kind = EventEquivocation
+		}
+		return e, nil
+	case consistency == nil && prev.Checkpoint.Size > 0:
+		return Event{}, fmt.Errorf("%w from size %d to %d for %q", ErrConsistencyProofRequired, prev.Checkpoint.Size, cp.Size, cp.Origin)
+	default:
+		if err := VerifyConsistency(t.alg, prev.Checkpoint.Size, cp.Size, consistency, prev.Checkpoint.Hash, cp.Hash); err != nil {
+			return Event{}, fmt.Errorf("%w from size %d to %d for %q: %w", ErrInvalidConsistencyProof, prev.Checkpoint.Size, cp.Size, cp.Origin, err)
+		}
+	}
+	if err := t.store.SetLatest(id, e.Current); err != nil {
+		return Event{}, fmt.Errorf("failed to store latest checkpoint for %q: %w", cp.Origin, err)
+	}
+	e.Kind = EventAdvanced
+	return e, nil
+}
+
+// MemoryCheckpointStore is a CheckpointStore which keeps checkpoints in memory.
+type MemoryCheckpointStore struct {
+	mu  sync.RWMutex
+	cps map[string]TrackedCheckpoint
+}
+
+// NewMemoryCheckpointStore returns an empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{cps: make(map[string]TrackedCheckpoint)}
+}
+
+// Latest implements CheckpointStore.
+func (s *MemoryCheckpointStore) Latest(id string) (*TrackedCheckpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tc, ok := s.cps[id]
+	if !ok {
+		return nil, nil
+	}
+	return &tc, nil
+}
+
+// SetLatest implements CheckpointStore.
+func (s *MemoryCheckpointStore) SetLatest(id string, tc TrackedCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cps[id] = tc
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore which keeps checkpoints in files
+// in a directory, one file per log named after the log ID.
+//
+// Each file holds the signed note for the checkpoint if it is known, or just
+// the checkpoint body otherwise.
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore which keeps
+// checkpoints in the provided directory, creating it if necessary.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// Latest implements CheckpointStore.
+func (s *FileCheckpointStore) Latest(id string) (*TrackedCheckpoint, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	tc := &TrackedCheckpoint{}
+	if _, err := tc.Checkpoint.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("stored checkpoint for %s is invalid: %w", id, err)
+	}
+	if !bytes.Equal(data, tc.Checkpoint.Marshal()) {
+		tc.Signed = data
+	}
+	return tc, nil
+}
+
+// SetLatest implements CheckpointStore.
+//
+// The file is replaced atomically, so readers always see either the previous
+// or the new checkpoint.
+func (s *FileCheckpointStore) SetLatest(id string, tc TrackedCheckpoint) error {
+	data := tc.Signed
+	if data == nil {
+		data = tc.Checkpoint.Marshal()
+	}
+	f, err := os.CreateTemp(s.dir, id+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(s.dir, id))
+}
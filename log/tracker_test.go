@@ -0,0 +1,196 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/transparency-dev/formats/internal/merkletest"
+	"github.com/transparency-dev/formats/log"
+)
+
+func TestTracker(t *testing.T) {
+	const origin = "example.com/log"
+	leaves := merkletest.Leaves(20)
+	cpAt := func(size int) log.Checkpoint {
+		return log.Checkpoint{Origin: origin, Size: uint64(size), Hash: merkletest.Root(log.SHA256, leaves[:size])}
+	}
+	proof := func(from, to int) [][]byte {
+		return merkletest.Consistency(log.SHA256, from, leaves[:to], true)
+	}
+	forked := cpAt(7)
+	forked.Hash = merkletest.Root(log.SHA256, merkletest.Leaves(6))
+
+	fileStore, err := log.NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCheckpointStore: %v", err)
+	}
+	for _, s := range []struct {
+		name  string
+		store log.CheckpointStore
+	}{
+		{name: "memory", store: log.NewMemoryCheckpointStore()},
+		{name: "file", store: fileStore},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			tr := log.NewTracker(s.store, log.SHA256)
+			for _, step := range []struct {
+				desc        string
+				cp          log.Checkpoint
+				signed      []byte
+				consistency [][]byte
+				wantKind    log.EventKind
+				wantErr     error
+				wantLatest  log.Checkpoint
+			}{
+				{
+					desc:       "first checkpoint",
+					cp:         cpAt(3),
+					wantKind:   log.EventAdvanced,
+					wantLatest: cpAt(3),
+				}, {
+					desc:        "consistent growth",
+					cp:          cpAt(7),
+					signed:      append(cpAt(7).Marshal(), "\n— sig\n"...),
+					consistency: proof(3, 7),
+					wantKind:    log.EventAdvanced,
+					wantLatest:  cpAt(7),
+				}, {
+					desc:       "smaller checkpoint",
+					cp:         cpAt(5),
+					wantKind:   log.EventStale,
+					wantLatest: cpAt(7),
+				}, {
+					desc:       "same checkpoint",
+					cp:         cpAt(7),
+					wantKind:   log.EventUnchanged,
+					wantLatest: cpAt(7),
+				}, {
+					desc:       "same size different root",
+					cp:         forked,
+					wantKind:   log.EventEquivocation,
+					wantLatest: cpAt(7),
+				}, {
+					desc:       "growth without proof",
+					cp:         cpAt(10),
+					wantErr:    log.ErrConsistencyProofRequired,
+					wantLatest: cpAt(7),
+				}, {
+					desc:        "growth with bad proof",
+					cp:          cpAt(10),
+					consistency: proof(6, 10),
+					wantErr:     log.ErrInvalidConsistencyProof,
+					wantLatest:  cpAt(7),
+				}, {
+					desc:        "growth after errors",
+					cp:          cpAt(20),
+					consistency: proof(7, 20),
+					wantKind:    log.EventAdvanced,
+					wantLatest:  cpAt(20),
+				},
+			} {
+				e, err := tr.Update(step.cp, step.signed, step.consistency)
+				if !errors.Is(err, step.wantErr) {
+					t.Fatalf("%s: Update = %v, want %v", step.desc, err, step.wantErr)
+				}
+				if err == nil && e.Kind != step.wantKind {
+					t.Errorf("%s: got event %v, want %v", step.desc, e.Kind, step.wantKind)
+				}
+				if err == nil && e.ID != log.ID(origin) {
+					t.Errorf("%s: got event ID %q, want %q", step.desc, e.ID, log.ID(origin))
+				}
+				latest, err := s.store.Latest(log.ID(origin))
+				if err != nil {
+					t.Fatalf("%s: Latest: %v", step.desc, err)
+				}
+				if diff := cmp.Diff(step.wantLatest, latest.Checkpoint); diff != "" {
+					t.Errorf("%s: latest checkpoint diff: %s", step.desc, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestTracker_ProofRequired(t *testing.T) {
+	tr := log.NewTracker(log.NewMemoryCheckpointStore(), log.SHA256)
+	leaves := merkletest.Leaves(2)
+	if _, err := tr.Update(log.Checkpoint{Origin: "o", Size: 1, Hash: merkletest.Root(log.SHA256, leaves[:1])}, nil, nil); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	_, err := tr.Update(log.Checkpoint{Origin: "o", Size: 2, Hash: merkletest.Root(log.SHA256, leaves)}, nil, nil)
+	if !errors.Is(err, log.ErrConsistencyProofRequired) {
+		t.Errorf("Update = %v, want %v", err, log.ErrConsistencyProofRequired)
+	}
+}
+
+func TestTracker_InvalidProof(t *testing.T) {
+	tr := log.NewTracker(log.NewMemoryCheckpointStore(), log.SHA256)
+	leaves := merkletest.Leaves(4)
+	if _, err := tr.Update(log.Checkpoint{Origin: "o", Size: 2, Hash: merkletest.Root(log.SHA256, leaves[:2])}, nil, nil); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	cp := log.Checkpoint{Origin: "o", Size: 4, Hash: merkletest.Root(log.SHA256, leaves)}
+	for _, test := range []struct {
+		desc        string
+		consistency [][]byte
+		wantErr     error
+	}{
+		{desc: "wrong hashes", consistency: [][]byte{log.SHA256.HashLeaf([]byte("other"))}, wantErr: log.ErrRootMismatch},
+		{desc: "too many hashes", consistency: append(merkletest.Consistency(log.SHA256, 2, leaves, true), cp.Hash), wantErr: log.ErrMalformedProof},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := tr.Update(cp, nil, test.consistency)
+			if !errors.Is(err, log.ErrInvalidConsistencyProof) || !errors.Is(err, test.wantErr) {
+				t.Errorf("Update = %v, want %v wrapping %v", err, log.ErrInvalidConsistencyProof, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := log.NewFileCheckpointStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileCheckpointStore: %v", err)
+	}
+	if tc, err := s.Latest("unknown"); err != nil || tc != nil {
+		t.Errorf("Latest(unknown) = %v, %v, want nil, nil", tc, err)
+	}
+
+	cp := log.Checkpoint{Origin: "example.com/log", Size: 1, Hash: []byte("root")}
+	signed := append(cp.Marshal(), "\n— example.com/log c2lnbmF0dXJl\n"...)
+	for _, want := range []log.TrackedCheckpoint{
+		{Checkpoint: cp},
+		{Checkpoint: cp, Signed: signed},
+	} {
+		if err := s.SetLatest("id", want); err != nil {
+			t.Fatalf("SetLatest: %v", err)
+		}
+		// A fresh store on the same directory must see the same state.
+		s2, err := log.NewFileCheckpointStore(dir)
+		if err != nil {
+			t.Fatalf("NewFileCheckpointStore: %v", err)
+		}
+		got, err := s2.Latest("id")
+		if err != nil {
+			t.Fatalf("Latest: %v", err)
+		}
+		if diff := cmp.Diff(&want, got); diff != "" {
+			t.Errorf("Latest diff: %s", diff)
+		}
+	}
+}
@@ -40,7 +40,7 @@ const (
 // GenerateMLDSAKey generates a named signer and verifier key pair. The signer key skey is private and must be kept secret.
 func GenerateMLDSAKey(name string) (skey string, vkey string, err error) {
 	if !isValidName(name) {
-		return "", "", errSignerID
+		return "", "", ErrSignerID
 	}
 	secK, err := mldsa.GenerateKey(mldsa.MLDSA44())
 	if err != nil {
@@ -65,11 +65,11 @@ func NewMLDSASigner(skey string) (SubtreeSigner, error) {
 	hash16, key64, _ := strings.Cut(skey, "+")
 	key, err := base64.StdEncoding.DecodeString(key64)
 	if priv1 != "PRIVATE" || priv2 != "KEY" || len(hash16) != 8 || err != nil || !isValidName(name) || len(key) == 0 {
-		return nil, errSignerID
+		return nil, ErrSignerID
 	}
 	alg, key := key[0], key[1:]
 	if alg != algMLDSA44 {
-		return nil, errSignerID
+		return nil, ErrSignerID
 	}
 	return newMLDSASigner(name, key)
 }
@@ -79,7 +79,7 @@ func NewMLDSASigner(skey string) (SubtreeSigner, error) {
 func newMLDSASigner(name string, keyBytes []byte) (*subtreeSigner, error) {
 	s := &subtreeSigner{name: name}
 	if len(keyBytes) != mldsa.PrivateKeySize {
-		return nil, errSignerID
+		return nil, ErrSignerID
 	}
 	key, err := mldsa.NewPrivateKey(mldsa.MLDSA44(), keyBytes)
 	if err != nil {
@@ -129,11 +129,11 @@ func NewMLDSAVerifier(vkey string) (SubtreeVerifier, error) {
 	hash16, key64, _ := strings.Cut(vkey, "+")
 	keyBytes, err := base64.StdEncoding.DecodeString(key64)
 	if len(hash16) != 8 || err != nil || !isValidName(name) || len(keyBytes) != mldsa.MLDSA44PublicKeySize+1 {
-		return nil, errVerifierID
+		return nil, ErrVerifierID
 	}
 	alg, pubKeyBytes := keyBytes[0], keyBytes[1:]
 	if alg != algMLDSA44 {
-		return nil, errVerifierID
+		return nil, ErrVerifierID
 	}
 
 	v := &subtreeVerifier{
@@ -160,60 +160,119 @@ func NewMLDSAVerifier(vkey string) (SubtreeVerifier, error) {
 // - an Ed25519 cosignature/v1 encoded signer key (algo ID 0x04)
 // - an ML-DSA-44 cosignature/v1 encoded signer key (algo ID 0x06)
 //
-// See https://c2sp.org/tlog-cosignature for more details.
+// See https://c2sp.org/tlog-cosignature for more details. That specification
+// only defines subtree/v1 signatures for ML-DSA-44 keys; see
+// NewSubtreeSignerForCosignatureV1 for how Ed25519 keys sign subtrees.
 func NewSignerForCosignatureV1(skey string) (Signer, error) {
+	stSigner, err := newSubtreeSignerForCosignatureV1(skey)
+	if err != nil {
+		return nil, err
+	}
+	return &signer{
+		name:      stSigner.name,
+		hash:      stSigner.hash,
+		sign:      stSigner.Sign,
+		verify:    stSigner.verifier.verifyNote,
+		timestamp: cosigV1SigTimestamp,
+	}, nil
+}
+
+// NewSubtreeSignerForCosignatureV1 constructs a new SubtreeSigner using the
+// provided skey-formatted key, which may be of any of the types supported by
+// NewSignerForCosignatureV1.
+//
+// https://c2sp.org/tlog-cosignature does not define subtree/v1 signatures
+// for Ed25519 keys, only cosignature/v1 signatures over checkpoints. As a
+// substitute, an Ed25519 SubtreeSigner signs a subtree starting at the
+// beginning of the log with the cosignature/v1 signature over the checkpoint
+// of the same size and root hash, which is not a subtree/v1 signature and is
+// only verified by verifiers from this package. Signing any other subtree
+// returns an error wrapping ErrUnsupportedSubtree, see SupportsSubtree.
+func NewSubtreeSignerForCosignatureV1(skey string) (SubtreeSigner, error) {
+	s, err := newSubtreeSignerForCosignatureV1(skey)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newSubtreeSignerForCosignatureV1(skey string) (*subtreeSigner, error) {
 	priv1, skey, _ := strings.Cut(skey, "+")
 	priv2, skey, _ := strings.Cut(skey, "+")
 	name, skey, _ := strings.Cut(skey, "+")
 	hash16, key64, _ := strings.Cut(skey, "+")
 	key, err := base64.StdEncoding.DecodeString(key64)
 	if priv1 != "PRIVATE" || priv2 != "KEY" || len(hash16) != 8 || err != nil || !isValidName(name) || len(key) == 0 {
-		return nil, errSignerID
+		return nil, ErrSignerID
 	}
 
-	s := &signer{name: name}
-
 	alg, key := key[0], key[1:]
 	switch alg {
-	default:
-		return nil, errSignerAlg
-
 	case algEd25519, algEd25519CosignatureV1:
-		if len(key) != ed25519.SeedSize {
-			return nil, errSignerID
-		}
-		key := ed25519.NewKeyFromSeed(key)
-		pubkey := append([]byte{algEd25519CosignatureV1}, key.Public().(ed25519.PublicKey)...)
-		s.hash = keyHashEd25519(name, pubkey)
-		s.sign = func(msg []byte) ([]byte, error) {
-			t := uint64(time.Now().Unix())
-			m, err := formatEd25519CosignatureV1(t, msg)
-			if err != nil {
-				return nil, err
-			}
-
-			// The signature itself is encoded as timestamp || signature.
-			sig := make([]byte, 0, timestampSize+ed25519.SignatureSize)
-			sig = binary.BigEndian.AppendUint64(sig, t)
-			sig = append(sig, ed25519.Sign(key, m)...)
-			return sig, nil
-		}
-		s.verify = verifyEd25519CosigV1(pubkey[1:])
-
+		return newEd25519CosigV1Signer(name, key)
 	case algMLDSA44:
-		stSigner, err := newMLDSASigner(name, key)
+		return newMLDSASigner(name, key)
+	default:
+		return nil, ErrSignerAlg
+	}
+}
+
+// newEd25519CosigV1Signer returns a signer for Ed25519 cosignature/v1, with
+// the provided name and private key seed.
+func newEd25519CosigV1Signer(name string, seed []byte) (*subtreeSigner, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrSignerID
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+	pubKey := key.Public().(ed25519.PublicKey)
+	s := &subtreeSigner{
+		name: name,
+		hash: keyHashEd25519(name, append([]byte{algEd25519CosignatureV1}, pubKey...)),
+	}
+	sign := func(t uint64, msg []byte) ([]byte, error) {
+		m, err := formatEd25519CosignatureV1(t, msg)
 		if err != nil {
 			return nil, err
 		}
-		s.sign = stSigner.Sign
-		s.verify = stSigner.verifier.verifyNote
-		s.hash = stSigner.hash
-	}
 
+		// The signature itself is encoded as timestamp || signature.
+		sig := make([]byte, 0, timestampSize+ed25519.SignatureSize)
+		sig = binary.BigEndian.AppendUint64(sig, t)
+		sig = append(sig, ed25519.Sign(key, m)...)
+		return sig, nil
+	}
+	s.signNote = func(msg []byte) ([]byte, error) {
+		return sign(uint64(time.Now().Unix()), msg)
+	}
+	s.signSubtree = func(timestamp uint64, logOrigin string, start, end uint64, root []byte) ([]byte, error) {
+		if start != 0 {
+			return nil, fmt.Errorf("%w: Ed25519 cosignatures cannot cover subtree [%d, %d) as it does not start at 0", ErrUnsupportedSubtree, start, end)
+		}
+		return sign(timestamp, log.Checkpoint{Origin: logOrigin, Size: end, Hash: root}.Marshal())
+	}
+	s.verifier = newEd25519CosigV1Verifier(name, s.hash, pubKey)
 	return s, nil
 }
 
-// NewVerifierForCosignatureV1 constructs a new Verifier for timestamped
+// newEd25519CosigV1Verifier returns a verifier for Ed25519 cosignature/v1
+// signatures from the provided key.
+//
+// Subtree signatures are only verified for subtrees starting at the beginning
+// of the log, as the cosignature/v1 signature over the equivalent checkpoint.
+func newEd25519CosigV1Verifier(name string, keyHash uint32, key []byte) *subtreeVerifier {
+	verifyNote := verifyEd25519CosigV1(key)
+	return &subtreeVerifier{
+		name:       name,
+		keyHash:    keyHash,
+		verifyNote: verifyNote,
+		verifySubtree: func(timestamp uint64, logOrigin string, start, end uint64, hash []byte, sig []byte) bool {
+			return start == 0 && verifyNote(log.Checkpoint{Origin: logOrigin, Size: end, Hash: hash}.Marshal(), sig)
+		},
+		checkpointOnly: true,
+	}
+}
+
+// NewVerifierForCosignatureV1 constructs a new SubtreeVerifier for timestamped
 // cosignature/v1 signatures from the provided vkey-formatted public key.
 //
 // Supported vkey types are:
@@ -225,43 +284,47 @@ func NewSignerForCosignatureV1(skey string) (Signer, error) {
 // be internally treated as an Ed25519 CosignatureV1 key (type 0x04), meaning
 // the returned Verifier has a different key hash from a non-timestamped Ed25519
 // verifier key.
-func NewVerifierForCosignatureV1(vkey string) (note.Verifier, error) {
+//
+// An Ed25519 key only verifies subtree signatures over subtrees starting at
+// the beginning of the log, as the cosignature/v1 signatures substituted by
+// NewSubtreeSignerForCosignatureV1, see SupportsSubtree.
+func NewVerifierForCosignatureV1(vkey string) (SubtreeVerifier, error) {
 	name, vkey, _ := strings.Cut(vkey, "+")
 	hash16, key64, _ := strings.Cut(vkey, "+")
 	key, err := base64.StdEncoding.DecodeString(key64)
 	if len(hash16) != 8 || err != nil || !isValidName(name) || len(key) == 0 {
-		return nil, errVerifierID
-	}
-
-	v := &verifier{
-		name: name,
+		return nil, ErrVerifierID
 	}
 
 	alg, key := key[0], key[1:]
 	switch alg {
-	default:
-		return nil, errVerifierAlg
-
 	case algEd25519, algEd25519CosignatureV1:
-		if len(key) != 32 {
-			return nil, errVerifierID
+		if len(key) != ed25519.PublicKeySize {
+			return nil, ErrVerifierID
 		}
-		v.keyHash = keyHashEd25519(name, append([]byte{algEd25519CosignatureV1}, key...))
-		v.v = verifyEd25519CosigV1(key)
+		keyHash := keyHashEd25519(name, append([]byte{algEd25519CosignatureV1}, key...))
+		return newEd25519CosigV1Verifier(name, keyHash, key), nil
 
 	case algMLDSA44:
 		if len(key) != mldsa.MLDSA44PublicKeySize {
-			return nil, errVerifierID
+			return nil, ErrVerifierID
 		}
-		v.keyHash = keyHashMLDSA(name, append([]byte{algMLDSA44}, key...))
 		pubKey, err := mldsa.NewPublicKey(mldsa.MLDSA44(), key)
 		if err != nil {
 			return nil, err
 		}
-		v.v = verifyMLDSACosigV1(pubKey, name)
-	}
+		return &subtreeVerifier{
+			name:       name,
+			keyHash:    keyHashMLDSA(name, append([]byte{algMLDSA44}, key...)),
+			verifyNote: verifyMLDSACosigV1(pubKey, name),
+			verifySubtree: func(timestamp uint64, logOrigin string, start, end uint64, hash []byte, sig []byte) bool {
+				return verifyMLDSACosigV1Subtree(pubKey, name)(logOrigin, start, end, hash, sig)
+			},
+		}, nil
 
-	return v, nil
+	default:
+		return nil, ErrVerifierAlg
+	}
 }
 
 // VKeyToCosignatureV1 converts a standard Ed25519 vkey to an Ed25519CosignatureV1 vkey.
@@ -270,23 +333,23 @@ func VKeyToCosignatureV1(vkey string) (string, error) {
 	hash16, key64, _ := strings.Cut(vkey, "+")
 	algKey, err := base64.StdEncoding.DecodeString(key64)
 	if len(hash16) != 8 || err != nil || !isValidName(name) || len(algKey) == 0 {
-		return "", errVerifierID
+		return "", ErrVerifierID
 	}
 
 	alg, key := algKey[0], algKey[1:]
 	if alg != algEd25519 {
-		return "", errVerifierAlg
+		return "", ErrVerifierAlg
 	}
 	hash, err := strconv.ParseUint(hash16, 16, 32)
 	if err != nil {
-		return "", errInvalidHash
+		return "", ErrInvalidHash
 	}
 
 	if uint32(hash) != keyHashEd25519(name, algKey) {
-		return "", errInvalidHash
+		return "", ErrInvalidHash
 	}
 	if len(key) != 32 {
-		return "", errVerifierID
+		return "", ErrVerifierID
 	}
 	pubKey := append([]byte{algEd25519CosignatureV1}, key...)
 	h := keyHashEd25519(name, pubKey)
@@ -298,11 +361,11 @@ func VKeyToCosignatureV1(vkey string) (string, error) {
 func CoSigV1Timestamp(s note.Signature) (time.Time, error) {
 	r, err := base64.StdEncoding.DecodeString(s.Base64)
 	if err != nil {
-		return time.UnixMilli(0), errMalformedSig
+		return time.UnixMilli(0), ErrMalformedSig
 	}
 	const minSigSize = 64 // min(ed25519.SignatureSize, mldsa.MLDSA44SignatureSize)
 	if len(r) < keyHashSize+timestampSize+minSigSize {
-		return time.UnixMilli(0), errVerifierAlg
+		return time.UnixMilli(0), ErrVerifierAlg
 	}
 	r = r[keyHashSize:] // Skip the hash
 	// Next 8 bytes are the timestamp as Unix seconds-since-epoch:
@@ -388,10 +451,10 @@ func formatEd25519CosignatureV1(t uint64, msg []byte) ([]byte, error) {
 func formatMLDSACosignatureV1(cosignerName string, timestamp uint64, logOrigin string, start, end uint64, hash []byte) ([]byte, error) {
 	// SPEC: If start is not zero, timestamp MUST be zero.
 	if start > 0 && timestamp > 0 {
-		return nil, errInvalidTimestamp
+		return nil, ErrInvalidTimestamp
 	}
 	if len(logOrigin) > 255 || len(cosignerName) > 255 {
-		return nil, errSignerID
+		return nil, ErrSignerID
 	}
 
 	// The signed message is a binary TLS presentation encoding of the
@@ -422,14 +485,28 @@ func formatMLDSACosignatureV1(cosignerName string, timestamp uint64, logOrigin s
 	return r.Bytes()
 }
 
+// Errors returned when constructing signers and verifiers, or when handling
+// signatures. Errors returned by this package wrap one of these where
+// applicable, and can be checked for with errors.Is.
 var (
-	errSignerID         = errors.New("malformed signer id")
-	errSignerAlg        = errors.New("unknown signer algorithm")
-	errVerifierID       = errors.New("malformed verifier id")
-	errVerifierAlg      = errors.New("unknown verifier algorithm")
-	errInvalidHash      = errors.New("invalid key hash")
-	errMalformedSig     = errors.New("malformed signature")
-	errInvalidTimestamp = errors.New("invalid timestamp")
+	// ErrSignerID is returned for a signer key which is not well formed.
+	ErrSignerID = errors.New("malformed signer id")
+	// ErrSignerAlg is returned for a signer key of an unsupported algorithm.
+	ErrSignerAlg = errors.New("unknown signer algorithm")
+	// ErrVerifierID is returned for a verifier key which is not well formed.
+	ErrVerifierID = errors.New("malformed verifier id")
+	// ErrVerifierAlg is returned for a verifier key of an unsupported
+	// algorithm.
+	ErrVerifierAlg = errors.New("unknown verifier algorithm")
+	// ErrInvalidHash is returned when a key hash does not match its key.
+	ErrInvalidHash = errors.New("invalid key hash")
+	// ErrMalformedSig is returned for a signature which is not well formed.
+	ErrMalformedSig = errors.New("malformed signature")
+	// ErrInvalidTimestamp is returned for a timestamp which cannot be signed.
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	// ErrUnsupportedSubtree is returned for a subtree which cannot be signed
+	// with the key's algorithm.
+	ErrUnsupportedSubtree = errors.New("subtree not supported by key")
 )
 
 // Signer is a note.Signer which also provides access to the corresponding Verifier.
@@ -444,6 +521,9 @@ type signer struct {
 	hash   uint32
 	sign   func([]byte) ([]byte, error)
 	verify func(msg, sig []byte) bool
+	// timestamp extracts the timestamp embedded in signatures, and is nil
+	// for signature types which do not have one.
+	timestamp func(sig []byte) (time.Time, bool)
 }
 
 func (s *signer) Name() string                    { return s.name }
@@ -452,9 +532,10 @@ func (s *signer) Sign(msg []byte) ([]byte, error) { return s.sign(msg) }
 
 func (s *signer) Verifier() note.Verifier {
 	return &verifier{
-		name:    s.name,
-		keyHash: s.hash,
-		v:       s.verify,
+		name:      s.name,
+		keyHash:   s.hash,
+		v:         s.verify,
+		timestamp: s.timestamp,
 	}
 }
 
@@ -489,6 +570,34 @@ type SubtreeVerifier interface {
 	VerifySubtree(timestamp uint64, logOrigin string, start, end uint64, hash []byte, sig []byte) bool
 }
 
+// Subtree identifies the subtree of a log covering the entries [Start, End),
+// and its root hash.
+type Subtree struct {
+	Origin string
+	Start  uint64
+	End    uint64
+	Hash   []byte
+}
+
+// SubtreeSignature is a cosignature over a Subtree, as returned by
+// SubtreeSigner.SignSubtree, from the key with the given name and key hash.
+type SubtreeSignature struct {
+	Name string
+	Hash uint32
+	// Sig is the encoded signature, timestamp || signature.
+	Sig []byte
+}
+
+// VerifySubtreeSignature returns true if sig is a valid cosignature over st
+// from v.
+func VerifySubtreeSignature(v SubtreeVerifier, st Subtree, sig SubtreeSignature) bool {
+	if sig.Name != v.Name() || sig.Hash != v.KeyHash() || len(sig.Sig) < timestampSize {
+		return false
+	}
+	t := binary.BigEndian.Uint64(sig.Sig)
+	return v.VerifySubtree(t, st.Origin, st.Start, st.End, st.Hash, sig.Sig)
+}
+
 // subtreeVerifier implements the note.Verifier interface to facilitate cosigning operations
 // against tree roots represented as checkpoints, but it can also be used to verify
 // arbitrary subtree roots using the VerifySubtree method.
@@ -497,6 +606,9 @@ type subtreeVerifier struct {
 	keyHash       uint32
 	verifyNote    func([]byte, []byte) bool
 	verifySubtree func(timestamp uint64, logOrigin string, start, end uint64, hash []byte, sig []byte) bool
+	// checkpointOnly is set for verifiers which only verify subtrees as the
+	// equivalent checkpoint, and so only those starting at 0.
+	checkpointOnly bool
 }
 
 func (v *subtreeVerifier) Name() string                { return v.name }
@@ -506,6 +618,20 @@ func (v *subtreeVerifier) VerifySubtree(timestamp uint64, logOrigin string, star
 	return v.verifySubtree(timestamp, logOrigin, start, end, hash, sig)
 }
 
+// SupportsSubtree returns true if v is able to verify cosignatures over st.
+// This is false for Ed25519 verifiers from this package when st does not
+// start at the beginning of the log, see NewSubtreeSignerForCosignatureV1.
+// Other verifiers are assumed to support any subtree.
+func SupportsSubtree(v SubtreeVerifier, st Subtree) bool {
+	switch v := v.(type) {
+	case *subtreeVerifier:
+		return !v.checkpointOnly || st.Start == 0
+	case *validitySubtreeVerifier:
+		return SupportsSubtree(v.sv, st)
+	}
+	return true
+}
+
 // isValidName reports whether name is valid.
 // It must be non-empty and not have any Unicode spaces or pluses.
 func isValidName(name string) bool {
@@ -5,11 +5,15 @@
 package note
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/binary"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/transparency-dev/formats/log"
 	"golang.org/x/mod/sumdb/note"
 )
 
@@ -344,6 +348,70 @@ func TestSubtreeRoundtrip(t *testing.T) {
 	}
 }
 
+func TestEd25519SubtreeRoundtrip(t *testing.T) {
+	skey, vkey := mustGenerateEd25519Key(t, "ed25519")
+	signer, err := NewSubtreeSignerForCosignatureV1(skey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := NewVerifierForCosignatureV1(vkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.KeyHash() != verifier.KeyHash() {
+		t.Fatalf("Signer hash %08x != Verifier hash %08x", signer.KeyHash(), verifier.KeyHash())
+	}
+
+	origin := "test-log"
+	root := make([]byte, 32)
+	if _, err := rand.Read(root); err != nil {
+		t.Fatal(err)
+	}
+	timestamp := uint64(time.Now().Unix())
+
+	sig, err := signer.SignSubtree(timestamp, origin, 0, 10, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifier.VerifySubtree(timestamp, origin, 0, 10, root, sig) {
+		t.Error("Failed to verify valid subtree signature")
+	}
+	if verifier.VerifySubtree(timestamp, origin, 0, 11, root, sig) {
+		t.Error("VerifySubtree succeeded with wrong end")
+	}
+
+	// A subtree starting at 0 is signed as the checkpoint of the same size,
+	// so the signature also verifies as a cosignature over that checkpoint.
+	cp := log.Checkpoint{Origin: origin, Size: 10, Hash: root}.Marshal()
+	if !verifier.Verify(cp, sig) {
+		t.Error("Subtree signature does not verify as a checkpoint cosignature")
+	}
+	if !signer.Verifier().VerifySubtree(timestamp, origin, 0, 10, root, sig) {
+		t.Error("Signer's verifier failed to verify valid subtree signature")
+	}
+
+	if _, err := signer.SignSubtree(0, origin, 8, 10, root); !errors.Is(err, ErrUnsupportedSubtree) {
+		t.Errorf("SignSubtree(start > 0) = %v, want %v", err, ErrUnsupportedSubtree)
+	}
+	if verifier.VerifySubtree(timestamp, origin, 8, 10, root, sig) {
+		t.Error("VerifySubtree succeeded for subtree not starting at 0")
+	}
+
+	// The timestamp is covered by the signature, so cannot be changed.
+	tampered := bytes.Clone(sig)
+	binary.BigEndian.PutUint64(tampered, timestamp+1)
+	if verifier.VerifySubtree(timestamp+1, origin, 0, 10, root, tampered) {
+		t.Error("VerifySubtree succeeded with tampered timestamp")
+	}
+
+	if !SupportsSubtree(verifier, Subtree{Origin: origin, Start: 0, End: 10}) {
+		t.Error("SupportsSubtree() = false for subtree starting at 0")
+	}
+	if SupportsSubtree(verifier, Subtree{Origin: origin, Start: 8, End: 10}) {
+		t.Error("SupportsSubtree() = true for subtree not starting at 0")
+	}
+}
+
 func TestMLDSAInvalidTimestamp(t *testing.T) {
 	skey, _ := mustGenerateMLDSAKey(t, "mldsa")
 	signer, err := NewMLDSASigner(skey)
@@ -427,3 +495,42 @@ func mustGenerateMLDSAKey(t *testing.T, name string) (string, string) {
 	}
 	return skey, vkey
 }
+
+func TestVerifySubtreeSignature(t *testing.T) {
+	skey, vkey := mustGenerateMLDSAKey(t, "mldsa")
+	signer, err := NewMLDSASigner(skey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := NewVerifierForCosignatureV1(vkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := Subtree{Origin: "test-log", Start: 8, End: 16, Hash: make([]byte, 32)}
+	sig, err := signer.SignSubtree(0, st.Origin, st.Start, st.End, st.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	good := SubtreeSignature{Name: signer.Name(), Hash: signer.KeyHash(), Sig: sig}
+	if !VerifySubtreeSignature(sv, st, good) {
+		t.Error("VerifySubtreeSignature failed for valid signature")
+	}
+
+	for _, test := range []struct {
+		desc string
+		st   Subtree
+		sig  SubtreeSignature
+	}{
+		{desc: "wrong name", st: st, sig: SubtreeSignature{Name: "other", Hash: good.Hash, Sig: sig}},
+		{desc: "wrong key hash", st: st, sig: SubtreeSignature{Name: good.Name, Hash: good.Hash + 1, Sig: sig}},
+		{desc: "short signature", st: st, sig: SubtreeSignature{Name: good.Name, Hash: good.Hash, Sig: sig[:4]}},
+		{desc: "wrong range", st: Subtree{Origin: st.Origin, Start: 0, End: 16, Hash: st.Hash}, sig: good},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if VerifySubtreeSignature(sv, test.st, test.sig) {
+				t.Error("VerifySubtreeSignature succeeded, want failure")
+			}
+		})
+	}
+}
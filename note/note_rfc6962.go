@@ -36,7 +36,7 @@ import (
 // pubK is the public key of the log.
 func RFC6962VerifierString(logURL string, pubK crypto.PublicKey) (string, error) {
 	if !isValidName(logURL) {
-		return "", errors.New("invalid name")
+		return "", fmt.Errorf("%w: invalid name %q", ErrVerifierID, logURL)
 	}
 	pubSer, err := x509.MarshalPKIXPublicKey(pubK)
 	if err != nil {
@@ -54,7 +54,7 @@ func NewRFC6962Verifier(vkey string) (note.Verifier, error) {
 	hash16, key64, _ := strings.Cut(vkey, "+")
 	key, err := base64.StdEncoding.DecodeString(key64)
 	if len(hash16) != 8 || err != nil || !isValidName(name) || len(key) == 0 {
-		return nil, errVerifierID
+		return nil, ErrVerifierID
 	}
 
 	v := &rfc6962Verifer{
@@ -63,12 +63,12 @@ func NewRFC6962Verifier(vkey string) (note.Verifier, error) {
 
 	alg, key := key[0], key[1:]
 	if alg != algRFC6962STH {
-		return nil, errVerifierAlg
+		return nil, ErrVerifierAlg
 	}
 
 	pubK, err := x509.ParsePKIXPublicKey(key)
 	if err != nil {
-		return nil, errors.New("invalid key")
+		return nil, fmt.Errorf("%w: invalid key: %v", ErrVerifierID, err)
 	}
 
 	logID := sha256.Sum256(key)
@@ -115,10 +115,10 @@ func RFC6962STHToCheckpoint(j []byte, v note.Verifier) ([]byte, error) {
 func RFC6962STHTimestamp(s note.Signature) (time.Time, error) {
 	r, err := base64.StdEncoding.DecodeString(s.Base64)
 	if err != nil {
-		return time.UnixMilli(0), errMalformedSig
+		return time.UnixMilli(0), ErrMalformedSig
 	}
 	if len(r) <= keyHashSize+timestampSize {
-		return time.UnixMilli(0), errVerifierAlg
+		return time.UnixMilli(0), ErrVerifierAlg
 	}
 	r = r[keyHashSize:] // Skip the hash
 	// Next 8 bytes are the timestamp as Unix millis-since-epoch:
@@ -0,0 +1,196 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package note
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Errors returned when handling signed subtrees.
+var (
+	// ErrMalformedSubtree is returned when a signed subtree cannot be
+	// marshalled or unmarshalled.
+	ErrMalformedSubtree = errors.New("malformed signed subtree")
+	// ErrInvalidSubtreeSignature is returned when a signed subtree carries a
+	// signature from a known key which does not verify.
+	ErrInvalidSubtreeSignature = errors.New("invalid subtree signature")
+	// ErrUnverifiedSubtree is returned when a signed subtree carries no
+	// signatures from known keys.
+	ErrUnverifiedSubtree = errors.New("no signatures from known keys")
+)
+
+// subtreeHashSize is the size of the subtree hash covered by subtree/v1
+// cosignatures.
+const subtreeHashSize = 32
+
+// SignedSubtree is a Subtree along with cosignatures over it, which can be
+// passed between processes in its marshalled form.
+//
+// The marshalled form follows that of a signed note: the body is made up of
+// the origin, the start and end of the subtree separated by a space, and the
+// base64 encoded hash, each followed by a newline. This is followed by a blank
+// line, and then a line for each signature of the form
+//
+//	— <name> <base64(key hash || timestamp || signature)>
+//
+// where the key hash is 4 bytes, big-endian.
+type SignedSubtree struct {
+	Subtree
+	Sigs []SubtreeSignature
+}
+
+// Sign adds a cosignature over the subtree from s. The timestamp must be zero
+// unless the subtree starts at the beginning of the log, and Ed25519 signers
+// can only sign such subtrees.
+func (s *SignedSubtree) Sign(timestamp uint64, signer SubtreeSigner) error {
+	sig, err := signer.SignSubtree(timestamp, s.Origin, s.Start, s.End, s.Hash)
+	if err != nil {
+		return err
+	}
+	s.Sigs = append(s.Sigs, SubtreeSignature{Name: signer.Name(), Hash: signer.KeyHash(), Sig: sig})
+	return nil
+}
+
+// Marshal returns the marshalled form of the signed subtree.
+func (s SignedSubtree) Marshal() ([]byte, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	if len(s.Sigs) == 0 {
+		return nil, fmt.Errorf("%w: no signatures", ErrMalformedSubtree)
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n%d %d\n%s\n\n", s.Origin, s.Start, s.End, base64.StdEncoding.EncodeToString(s.Hash))
+	for _, sig := range s.Sigs {
+		if !isValidName(sig.Name) {
+			return nil, fmt.Errorf("%w: invalid signature name %q", ErrMalformedSubtree, sig.Name)
+		}
+		enc := binary.BigEndian.AppendUint32(nil, sig.Hash)
+		enc = append(enc, sig.Sig...)
+		fmt.Fprintf(&b, "— %s %s\n", sig.Name, base64.StdEncoding.EncodeToString(enc))
+	}
+	return b.Bytes(), nil
+}
+
+// Unmarshal parses the marshalled form of a signed subtree, as produced by
+// Marshal, into s. The signatures are not verified, see Verify.
+//
+// Errors wrap ErrMalformedSubtree.
+func (s *SignedSubtree) Unmarshal(data []byte) error {
+	body, sigs, ok := bytes.Cut(data, []byte("\n\n"))
+	if !ok {
+		return fmt.Errorf("%w: missing signatures", ErrMalformedSubtree)
+	}
+	lines := strings.Split(string(body), "\n")
+	if len(lines) != 3 {
+		return fmt.Errorf("%w: body has %d lines, want 3", ErrMalformedSubtree, len(lines))
+	}
+	var st SignedSubtree
+	st.Origin = lines[0]
+	startStr, endStr, _ := strings.Cut(lines[1], " ")
+	var err error
+	if st.Start, err = parseCanonicalUint(startStr); err != nil {
+		return fmt.Errorf("%w: invalid start %q", ErrMalformedSubtree, startStr)
+	}
+	if st.End, err = parseCanonicalUint(endStr); err != nil {
+		return fmt.Errorf("%w: invalid end %q", ErrMalformedSubtree, endStr)
+	}
+	if st.Hash, err = base64.StdEncoding.DecodeString(lines[2]); err != nil || base64.StdEncoding.EncodeToString(st.Hash) != lines[2] {
+		return fmt.Errorf("%w: invalid hash %q", ErrMalformedSubtree, lines[2])
+	}
+	if err := st.check(); err != nil {
+		return err
+	}
+
+	if len(sigs) == 0 || sigs[len(sigs)-1] != '\n' {
+		return fmt.Errorf("%w: signatures must end with a newline", ErrMalformedSubtree)
+	}
+	for _, line := range strings.Split(string(sigs[:len(sigs)-1]), "\n") {
+		rest, ok := strings.CutPrefix(line, "— ")
+		name, b64, ok2 := strings.Cut(rest, " ")
+		if !ok || !ok2 || !isValidName(name) {
+			return fmt.Errorf("%w: invalid signature line %q", ErrMalformedSubtree, line)
+		}
+		enc, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil || len(enc) < keyHashSize+timestampSize {
+			return fmt.Errorf("%w: invalid signature from %q", ErrMalformedSubtree, name)
+		}
+		st.Sigs = append(st.Sigs, SubtreeSignature{
+			Name: name,
+			Hash: binary.BigEndian.Uint32(enc),
+			Sig:  enc[keyHashSize:],
+		})
+	}
+	*s = st
+	return nil
+}
+
+// check returns an error if the subtree cannot be marshalled and signed.
+func (s SignedSubtree) check() error {
+	if s.Origin == "" || len(s.Origin) > 255 || strings.ContainsFunc(s.Origin, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		return fmt.Errorf("%w: invalid origin %q", ErrMalformedSubtree, s.Origin)
+	}
+	if s.Start >= s.End {
+		return fmt.Errorf("%w: empty subtree [%d, %d)", ErrMalformedSubtree, s.Start, s.End)
+	}
+	if len(s.Hash) != subtreeHashSize {
+		return fmt.Errorf("%w: hash is %d bytes, want %d", ErrMalformedSubtree, len(s.Hash), subtreeHashSize)
+	}
+	return nil
+}
+
+// parseCanonicalUint parses a decimal number without leading zeroes.
+func parseCanonicalUint(s string) (uint64, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err == nil && strconv.FormatUint(n, 10) != s {
+		return 0, strconv.ErrSyntax
+	}
+	return n, err
+}
+
+// Verify checks the signatures on the subtree from the given verifiers, and
+// returns those which are valid. Signatures from unknown keys are ignored.
+//
+// As with note.Open, it is an error for a signature from a known key not to
+// verify, or for there to be no signatures from known keys.
+func (s SignedSubtree) Verify(verifiers ...SubtreeVerifier) ([]SubtreeSignature, error) {
+	var verified []SubtreeSignature
+	seen := make(map[int]bool)
+	for _, sig := range s.Sigs {
+		i := slices.IndexFunc(verifiers, func(v SubtreeVerifier) bool { return v.Name() == sig.Name && v.KeyHash() == sig.Hash })
+		if i < 0 {
+			continue
+		}
+		if !VerifySubtreeSignature(verifiers[i], s.Subtree, sig) {
+			return nil, fmt.Errorf("%w from %s+%08x", ErrInvalidSubtreeSignature, sig.Name, sig.Hash)
+		}
+		if !seen[i] {
+			seen[i] = true
+			verified = append(verified, sig)
+		}
+	}
+	if len(verified) == 0 {
+		return nil, ErrUnverifiedSubtree
+	}
+	return verified, nil
+}
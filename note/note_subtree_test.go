@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package note
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func mustNewMLDSASigner(t *testing.T, name string) SubtreeSigner {
+	t.Helper()
+	skey, _ := mustGenerateMLDSAKey(t, name)
+	s, err := NewMLDSASigner(skey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSignedSubtree_RoundTrip(t *testing.T) {
+	s1, s2 := mustNewMLDSASigner(t, "w1"), mustNewMLDSASigner(t, "w2")
+	st := SignedSubtree{Subtree: Subtree{Origin: "example.com/log", Start: 256, End: 512, Hash: bytes.Repeat([]byte{0xab}, 32)}}
+	for _, s := range []SubtreeSigner{s1, s2} {
+		if err := st.Sign(0, s); err != nil {
+			t.Fatalf("Sign: %v", err)
+		}
+	}
+
+	b, err := st.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	wantBody := "example.com/log\n256 512\nq6urq6urq6urq6urq6urq6urq6urq6urq6urq6urq6s=\n\n"
+	if !bytes.HasPrefix(b, []byte(wantBody)) {
+		t.Errorf("Marshal() = %q, want prefix %q", b, wantBody)
+	}
+	var got SignedSubtree
+	if err := got.Unmarshal(b); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if diff := cmp.Diff(st, got); diff != "" {
+		t.Errorf("Unmarshal diff (-want +got):\n%s", diff)
+	}
+
+	sigs, err := got.Verify(s2.Verifier())
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if len(sigs) != 1 || sigs[0].Name != "w2" {
+		t.Errorf("Verify() = %v, want signature from w2", sigs)
+	}
+	if sigs, err := got.Verify(s1.Verifier(), s2.Verifier()); err != nil || len(sigs) != 2 {
+		t.Errorf("Verify(both) = %d signatures, %v, want 2", len(sigs), err)
+	}
+}
+
+func TestSignedSubtree_Verify(t *testing.T) {
+	s1, s2 := mustNewMLDSASigner(t, "w1"), mustNewMLDSASigner(t, "w2")
+	st := SignedSubtree{Subtree: Subtree{Origin: "example.com/log", Start: 0, End: 8, Hash: make([]byte, 32)}}
+	if err := st.Sign(1234, s1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.Verify(s2.Verifier()); !errors.Is(err, ErrUnverifiedSubtree) {
+		t.Errorf("Verify(unknown key) = %v, want %v", err, ErrUnverifiedSubtree)
+	}
+	tampered := st
+	tampered.End = 16
+	if _, err := tampered.Verify(s1.Verifier(), s2.Verifier()); !errors.Is(err, ErrInvalidSubtreeSignature) {
+		t.Errorf("Verify(tampered) = %v, want %v", err, ErrInvalidSubtreeSignature)
+	}
+	if err := (&SignedSubtree{Subtree: Subtree{Origin: "o", Start: 8, End: 16}}).Sign(1234, s1); err == nil {
+		t.Error("Sign() with timestamp for subtree not starting at 0 succeeded, want error")
+	}
+}
+
+func TestSignedSubtree_Unmarshal_Errors(t *testing.T) {
+	s := mustNewMLDSASigner(t, "w1")
+	st := SignedSubtree{Subtree: Subtree{Origin: "example.com/log", Start: 0, End: 8, Hash: make([]byte, 32)}}
+	if err := st.Sign(0, s); err != nil {
+		t.Fatal(err)
+	}
+	good, err := st.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, sigs, _ := strings.Cut(string(good), "\n\n")
+	hash := "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+
+	for _, test := range []struct {
+		desc string
+		data string
+	}{
+		{desc: "no signatures", data: body + "\n"},
+		{desc: "empty signatures", data: body + "\n\n"},
+		{desc: "too few lines", data: "example.com/log\n0 8\n\n" + sigs},
+		{desc: "checkpoint", data: "example.com/log\n8\n" + hash + "\n\n" + sigs},
+		{desc: "empty origin", data: "\n0 8\n" + hash + "\n\n" + sigs},
+		{desc: "non-canonical start", data: "example.com/log\n00 8\n" + hash + "\n\n" + sigs},
+		{desc: "empty range", data: "example.com/log\n8 8\n" + hash + "\n\n" + sigs},
+		{desc: "short hash", data: "example.com/log\n0 8\nAAAA\n\n" + sigs},
+		{desc: "bad signature line", data: body + "\n\n- w1 AAAA\n"},
+		{desc: "short signature", data: body + "\n\n— w1 AAAAAAAA\n"},
+		{desc: "missing final newline", data: strings.TrimSuffix(string(good), "\n")},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var got SignedSubtree
+			if err := got.Unmarshal([]byte(test.data)); !errors.Is(err, ErrMalformedSubtree) {
+				t.Errorf("Unmarshal(%q) = %v, want %v", test.data, err, ErrMalformedSubtree)
+			}
+		})
+	}
+}
+
+func TestSignedSubtree_Marshal_Errors(t *testing.T) {
+	sig := SubtreeSignature{Name: "w1", Sig: make([]byte, 16)}
+	for _, test := range []struct {
+		desc string
+		st   SignedSubtree
+	}{
+		{desc: "no signatures", st: SignedSubtree{Subtree: Subtree{Origin: "o", End: 1, Hash: make([]byte, 32)}}},
+		{desc: "origin with space", st: SignedSubtree{Subtree: Subtree{Origin: "o o", End: 1, Hash: make([]byte, 32)}, Sigs: []SubtreeSignature{sig}}},
+		{desc: "empty range", st: SignedSubtree{Subtree: Subtree{Origin: "o", Start: 1, End: 1, Hash: make([]byte, 32)}, Sigs: []SubtreeSignature{sig}}},
+		{desc: "bad hash", st: SignedSubtree{Subtree: Subtree{Origin: "o", End: 1, Hash: make([]byte, 20)}, Sigs: []SubtreeSignature{sig}}},
+		{desc: "bad signature name", st: SignedSubtree{Subtree: Subtree{Origin: "o", End: 1, Hash: make([]byte, 32)}, Sigs: []SubtreeSignature{{Name: "w+1"}}}},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := test.st.Marshal(); !errors.Is(err, ErrMalformedSubtree) {
+				t.Errorf("Marshal() = %v, want %v", err, ErrMalformedSubtree)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package note
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"time"
+
+	"golang.org/x/mod/sumdb/note"
+)
+
+// Validity constrains the checkpoints over which signatures from a key are
+// accepted, allowing keys to be rotated without continuing to trust retired
+// keys indefinitely.
+//
+// The zero value of each field imposes no constraint.
+type Validity struct {
+	// MinTreeSize and MaxTreeSize are inclusive bounds on the tree size of
+	// signed checkpoints. A MaxTreeSize of zero means there is no upper bound.
+	MinTreeSize uint64
+	MaxTreeSize uint64
+	// NotBefore and NotAfter are inclusive bounds on the timestamp embedded
+	// in signatures. If either is set, signatures which do not embed a
+	// timestamp, such as plain Ed25519 note signatures, are not accepted.
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// NewVerifierWithValidity returns a note.Verifier which only accepts
+// signatures from v over checkpoints which satisfy the provided validity
+// constraints.
+//
+// Signatures outside of the validity window are treated as invalid, so
+// note.Open will return an error for a note carrying them, just as for any
+// other bad signature from a known key.
+//
+// Timestamps are understood for signatures verified by the cosignature/v1
+// and RFC 6962 verifiers in this package.
+//
+// If v is a SubtreeVerifier then so is the returned Verifier, and the tree
+// size bounds are applied to the end of each signed subtree.
+func NewVerifierWithValidity(v note.Verifier, validity Validity) note.Verifier {
+	vv := &validityVerifier{Verifier: v, validity: validity}
+	if sv, ok := v.(SubtreeVerifier); ok {
+		return &validitySubtreeVerifier{validityVerifier: vv, sv: sv}
+	}
+	return vv
+}
+
+type validitySubtreeVerifier struct {
+	*validityVerifier
+	sv SubtreeVerifier
+}
+
+// VerifySubtree checks that sig is a valid subtree signature from the
+// underlying verifier, and that the subtree and sig fall within the validity
+// constraints.
+func (v *validitySubtreeVerifier) VerifySubtree(timestamp uint64, logOrigin string, start, end uint64, hash []byte, sig []byte) bool {
+	c := v.validity
+	if end < c.MinTreeSize || (c.MaxTreeSize > 0 && end > c.MaxTreeSize) {
+		return false
+	}
+	if !v.validTime(sig) {
+		return false
+	}
+	return v.sv.VerifySubtree(timestamp, logOrigin, start, end, hash, sig)
+}
+
+type validityVerifier struct {
+	note.Verifier
+	validity Validity
+}
+
+// Verify checks that sig is a valid signature over msg from the underlying
+// verifier, and that msg and sig fall within the validity constraints.
+func (v *validityVerifier) Verify(msg, sig []byte) bool {
+	return v.valid(msg, sig) && v.Verifier.Verify(msg, sig)
+}
+
+func (v *validityVerifier) valid(msg, sig []byte) bool {
+	c := v.validity
+	if c.MinTreeSize > 0 || c.MaxTreeSize > 0 {
+		size, ok := checkpointSize(msg)
+		if !ok || size < c.MinTreeSize || (c.MaxTreeSize > 0 && size > c.MaxTreeSize) {
+			return false
+		}
+	}
+	return v.validTime(sig)
+}
+
+// validTime returns true if the timestamp embedded in sig falls within the
+// validity constraints, or if there are no constraints on the timestamp.
+func (v *validityVerifier) validTime(sig []byte) bool {
+	c := v.validity
+	if c.NotBefore.IsZero() && c.NotAfter.IsZero() {
+		return true
+	}
+	t, ok := sigTimestamp(v.Verifier, sig)
+	return ok && (c.NotBefore.IsZero() || !t.Before(c.NotBefore)) && (c.NotAfter.IsZero() || !t.After(c.NotAfter))
+}
+
+// checkpointSize returns the tree size from the second line of a checkpoint
+// body.
+func checkpointSize(msg []byte) (uint64, bool) {
+	_, rest, _ := bytes.Cut(msg, []byte("\n"))
+	line, _, ok := bytes.Cut(rest, []byte("\n"))
+	if !ok {
+		return 0, false
+	}
+	size, err := strconv.ParseUint(string(line), 10, 64)
+	return size, err == nil
+}
+
+// sigTimestamp returns the timestamp embedded in a signature by v, if the
+// signature type is known to have one.
+func sigTimestamp(v note.Verifier, sig []byte) (time.Time, bool) {
+	switch v := v.(type) {
+	case *verifier:
+		if v.timestamp != nil {
+			return v.timestamp(sig)
+		}
+	case *subtreeVerifier:
+		return cosigV1SigTimestamp(sig)
+	case *rfc6962Verifer:
+		return rfc6962SigTimestamp(sig)
+	case *validityVerifier:
+		return sigTimestamp(v.Verifier, sig)
+	case *validitySubtreeVerifier:
+		return sigTimestamp(v.Verifier, sig)
+	}
+	return time.Time{}, false
+}
+
+// cosigV1SigTimestamp returns the timestamp, in seconds since the epoch, which
+// prefixes a cosignature/v1 signature.
+func cosigV1SigTimestamp(sig []byte) (time.Time, bool) {
+	if len(sig) < timestampSize {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(binary.BigEndian.Uint64(sig)), 0), true
+}
+
+// rfc6962SigTimestamp returns the timestamp, in milliseconds since the epoch,
+// which prefixes a translated RFC 6962 STH signature.
+func rfc6962SigTimestamp(sig []byte) (time.Time, bool) {
+	if len(sig) < timestampSize {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(sig))), true
+}
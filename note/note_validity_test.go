@@ -0,0 +1,197 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package note
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/mod/sumdb/note"
+)
+
+func TestVerifierWithValidity(t *testing.T) {
+	edSk, edVk := mustGenerateEd25519Key(t, "log")
+	logS, err := note.NewSigner(edSk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logV, err := note.NewVerifier(edVk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cosigSk, _ := mustGenerateEd25519Key(t, "witness")
+	cosigS, err := NewSignerForCosignatureV1(cosigSk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mlSk, _ := mustGenerateMLDSAKey(t, "mldsa")
+	mlS, err := NewSignerForCosignatureV1(mlSk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rfc6962V, err := NewRFC6962Verifier("rome.ct.filippo.io/2024h1+78f4abae+BTBZMBMGByqGSM49AgEGCCqGSM49AwEHA0IABAFzPC3fap+uINc1RQ4eRbYMUt84+bKkA8GDLN8KwVdzAgYhYSv4kS8XSheGLAHCWhIJTJbuC3sL88bNMTtrsBM=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const msg = "test\n123\nf+7CoKgXKE/tNys9TTXcr/ad6U/K3xvznmzew9y6SP0=\n"
+	sign := func(s note.Signer) []byte {
+		n, err := note.Sign(&note.Note{Text: msg}, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	now := time.Now()
+	// The rome checkpoint was signed on 2024-03-27.
+	romeTime := time.Date(2024, 3, 27, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name     string
+		n        []byte
+		v        note.Verifier
+		validity Validity
+		wantErr  bool
+	}{
+		{
+			name: "no constraints",
+			n:    sign(logS),
+			v:    logV,
+		}, {
+			name:     "size in range",
+			n:        sign(logS),
+			v:        logV,
+			validity: Validity{MinTreeSize: 123, MaxTreeSize: 123},
+		}, {
+			name:     "size below range",
+			n:        sign(logS),
+			v:        logV,
+			validity: Validity{MinTreeSize: 124},
+			wantErr:  true,
+		}, {
+			name:     "size above range",
+			n:        sign(logS),
+			v:        logV,
+			validity: Validity{MaxTreeSize: 122},
+			wantErr:  true,
+		}, {
+			name:     "no timestamp in signature",
+			n:        sign(logS),
+			v:        logV,
+			validity: Validity{NotBefore: now.Add(-time.Hour)},
+			wantErr:  true,
+		}, {
+			name:     "cosignature in window",
+			n:        sign(cosigS),
+			v:        cosigS.Verifier(),
+			validity: Validity{NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Hour)},
+		}, {
+			name:     "cosignature after window",
+			n:        sign(cosigS),
+			v:        cosigS.Verifier(),
+			validity: Validity{NotAfter: now.Add(-time.Hour)},
+			wantErr:  true,
+		}, {
+			name:     "cosignature before window",
+			n:        sign(cosigS),
+			v:        cosigS.Verifier(),
+			validity: Validity{NotBefore: now.Add(time.Hour)},
+			wantErr:  true,
+		}, {
+			name:     "mldsa cosignature in window",
+			n:        sign(mlS),
+			v:        mlS.Verifier(),
+			validity: Validity{NotBefore: now.Add(-time.Hour)},
+		}, {
+			name:     "rfc6962 in window",
+			n:        []byte(romeCP),
+			v:        rfc6962V,
+			validity: Validity{NotBefore: romeTime, NotAfter: romeTime.Add(24 * time.Hour)},
+		}, {
+			name:     "rfc6962 after window",
+			n:        []byte(romeCP),
+			v:        rfc6962V,
+			validity: Validity{NotAfter: romeTime},
+			wantErr:  true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewVerifierWithValidity(test.v, test.validity)
+			if v.Name() != test.v.Name() || v.KeyHash() != test.v.KeyHash() {
+				t.Errorf("got verifier %s+%08x, want %s+%08x", v.Name(), v.KeyHash(), test.v.Name(), test.v.KeyHash())
+			}
+			_, err := note.Open(test.n, note.VerifierList(v))
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("Open = %v, wantErr %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifierWithValidity_Subtree(t *testing.T) {
+	mlSkey, mlVkey := mustGenerateMLDSAKey(t, "mldsa")
+	edSkey, edVkey := mustGenerateEd25519Key(t, "ed25519")
+	for _, k := range []struct {
+		name       string
+		skey, vkey string
+	}{
+		{name: "ML-DSA-44", skey: mlSkey, vkey: mlVkey},
+		// Ed25519 subtree signatures are cosignature/v1 signatures over the
+		// equivalent checkpoint, whose timestamps must be checked in the same
+		// way.
+		{name: "Ed25519", skey: edSkey, vkey: edVkey},
+	} {
+		t.Run(k.name, func(t *testing.T) {
+			signer, err := NewSubtreeSignerForCosignatureV1(k.skey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := NewVerifierForCosignatureV1(k.vkey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			now := time.Now()
+			root := make([]byte, 32)
+			sig, err := signer.SignSubtree(uint64(now.Unix()), "test-log", 0, 10, root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, test := range []struct {
+				name     string
+				validity Validity
+				want     bool
+			}{
+				{name: "no constraints", want: true},
+				{name: "end in range", validity: Validity{MinTreeSize: 10, MaxTreeSize: 10}, want: true},
+				{name: "end below range", validity: Validity{MinTreeSize: 11}},
+				{name: "end above range", validity: Validity{MaxTreeSize: 9}},
+				{name: "time in range", validity: Validity{NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Hour)}, want: true},
+				{name: "time before range", validity: Validity{NotBefore: now.Add(time.Hour)}},
+				{name: "time after range", validity: Validity{NotAfter: now.Add(-time.Hour)}},
+			} {
+				t.Run(test.name, func(t *testing.T) {
+					sv, ok := NewVerifierWithValidity(v, test.validity).(SubtreeVerifier)
+					if !ok {
+						t.Fatal("NewVerifierWithValidity did not return a SubtreeVerifier")
+					}
+					if got := sv.VerifySubtree(0, "test-log", 0, 10, root, sig); got != test.want {
+						t.Errorf("VerifySubtree() = %t, want %t", got, test.want)
+					}
+				})
+			}
+		})
+	}
+}
@@ -16,16 +16,19 @@
 package note
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/mod/sumdb/note"
 )
@@ -41,22 +44,22 @@ func NewEd25519SignerVerifier(skey string) (note.Signer, note.Verifier, error) {
 	}
 	parts := strings.SplitN(skey, "+", 5)
 	if n := len(parts); n != 5 {
-		return nil, nil, fmt.Errorf("expected 5 parts but got %d", n)
+		return nil, nil, fmt.Errorf("%w: expected 5 parts but got %d", ErrSignerID, n)
 	}
 	if parts[0] != "PRIVATE" || parts[1] != "KEY" {
-		return nil, nil, fmt.Errorf("expected first tokens to be [PRIVATE, KEY]")
+		return nil, nil, fmt.Errorf("%w: expected first tokens to be [PRIVATE, KEY]", ErrSignerID)
 	}
 	key, err := base64.StdEncoding.DecodeString(parts[4])
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to decode base64: %v", err)
+		return nil, nil, fmt.Errorf("%w: failed to decode base64: %v", ErrSignerID, err)
 	}
 
 	alg, key := key[0], key[1:]
 	if alg != algEd25519 {
-		return nil, nil, errors.New("unsupported algorithm")
+		return nil, nil, fmt.Errorf("%w: unsupported algorithm", ErrSignerAlg)
 	}
 	if l := len(key); l != ed25519.SeedSize {
-		return nil, nil, fmt.Errorf("expected key seed of size %d but got %d", ed25519.SeedSize, l)
+		return nil, nil, fmt.Errorf("%w: expected key seed of size %d but got %d", ErrSignerID, ed25519.SeedSize, l)
 	}
 	publicKey := ed25519.NewKeyFromSeed(key).Public().(ed25519.PublicKey)
 	vkey, err := note.NewEd25519VerifierKey(s.Name(), publicKey)
@@ -75,14 +78,14 @@ func NewEd25519SignerVerifier(skey string) (note.Signer, note.Verifier, error) {
 func NewVerifier(key string) (note.Verifier, error) {
 	parts := strings.SplitN(key, "+", 3)
 	if got, want := len(parts), 3; got != want {
-		return nil, fmt.Errorf("key has %d parts, expected %d: %q", got, want, key)
+		return nil, fmt.Errorf("%w: key has %d parts, expected %d: %q", ErrVerifierID, got, want, key)
 	}
 	keyBytes, err := base64.StdEncoding.DecodeString(parts[2])
 	if err != nil {
-		return nil, fmt.Errorf("key has invalid base64 %q: %v", parts[2], err)
+		return nil, fmt.Errorf("%w: key has invalid base64 %q: %v", ErrVerifierID, parts[2], err)
 	}
 	if len(keyBytes) < 2 {
-		return nil, fmt.Errorf("invalid key, key bytes too short")
+		return nil, fmt.Errorf("%w: key bytes too short", ErrVerifierID)
 	}
 
 	switch keyBytes[0] {
@@ -102,6 +105,9 @@ type verifier struct {
 	name    string
 	keyHash uint32
 	v       func(msg, sig []byte) bool
+	// timestamp extracts the timestamp embedded in signatures, and is nil
+	// for signature types which do not have one.
+	timestamp func(sig []byte) (time.Time, bool)
 }
 
 // Name returns the name associated with the key this verifier is based on.
@@ -139,36 +145,36 @@ func (v *verifier) Verify(msg, sig []byte) bool {
 func NewECDSAVerifier(key string) (note.Verifier, error) {
 	parts := strings.SplitN(key, "+", 3)
 	if got, want := len(parts), 3; got != want {
-		return nil, fmt.Errorf("key has %d parts, expected %d: %q", got, want, key)
+		return nil, fmt.Errorf("%w: key has %d parts, expected %d: %q", ErrVerifierID, got, want, key)
 	}
 	keyBytes, err := base64.StdEncoding.DecodeString(parts[2])
 	if err != nil {
-		return nil, fmt.Errorf("key has invalid base64 %q: %v", parts[2], err)
+		return nil, fmt.Errorf("%w: key has invalid base64 %q: %v", ErrVerifierID, parts[2], err)
 	}
 	if len(keyBytes) < 2 {
-		return nil, fmt.Errorf("invalid key, key bytes too short")
+		return nil, fmt.Errorf("%w: key bytes too short", ErrVerifierID)
 	}
 	if keyBytes[0] != algECDSAWithSHA256 {
-		return nil, fmt.Errorf("key has incorrect type %d", keyBytes[0])
+		return nil, fmt.Errorf("%w: key has incorrect type %d", ErrVerifierAlg, keyBytes[0])
 	}
 	der := keyBytes[1:]
 	kh := keyHashECDSA(der)
 
 	khProvided, err := strconv.ParseUint(parts[1], 16, 32)
 	if err != nil {
-		return nil, fmt.Errorf("invalid key, couldn't parse keyhash: %v", err)
+		return nil, fmt.Errorf("%w: couldn't parse keyhash: %v", ErrVerifierID, err)
 	}
 	if uint32(khProvided) != kh {
-		return nil, fmt.Errorf("invalid keyhash %x, expected %x", khProvided, kh)
+		return nil, fmt.Errorf("%w: got %x, expected %x", ErrInvalidHash, khProvided, kh)
 	}
 
 	k, err := x509.ParsePKIXPublicKey(der)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't parse public key: %v", err)
+		return nil, fmt.Errorf("%w: couldn't parse public key: %v", ErrVerifierID, err)
 	}
 	ecdsaKey, ok := k.(*ecdsa.PublicKey)
 	if !ok {
-		return nil, fmt.Errorf("key is a %T, expected an ECDSA key", k)
+		return nil, fmt.Errorf("%w: key is a %T, expected an ECDSA key", ErrVerifierAlg, k)
 	}
 
 	return &verifier{
@@ -181,6 +187,115 @@ func NewECDSAVerifier(key string) (note.Verifier, error) {
 	}, nil
 }
 
+// ECDSAVerifierString creates a note style verifier string for use with
+// NewECDSAVerifier.
+// name is the name of the key.
+// pubK is the ECDSA public key.
+func ECDSAVerifierString(name string, pubK crypto.PublicKey) (string, error) {
+	if !isValidName(name) {
+		return "", fmt.Errorf("%w: invalid name %q", ErrVerifierID, name)
+	}
+	if _, ok := pubK.(*ecdsa.PublicKey); !ok {
+		return "", fmt.Errorf("%w: key is a %T, expected an ECDSA key", ErrVerifierAlg, pubK)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pubK)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s+%08x+%s", name, keyHashECDSA(der), base64.StdEncoding.EncodeToString(append([]byte{algECDSAWithSHA256}, der...))), nil
+}
+
+// GenerateECDSAKey generates a named P-256 signer and verifier key pair for
+// use with NewECDSASigner and NewECDSAVerifier. The signer key skey is private
+// and must be kept secret.
+func GenerateECDSAKey(name string) (skey string, vkey string, err error) {
+	if !isValidName(name) {
+		return "", "", ErrSignerID
+	}
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	vkey, err = ECDSAVerifierString(name, &k.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(k)
+	if err != nil {
+		return "", "", err
+	}
+	skey = fmt.Sprintf("PRIVATE+KEY+%s+%08x+%s", name, keyHashECDSA(pubDER), base64.StdEncoding.EncodeToString(append([]byte{algECDSAWithSHA256}, der...)))
+	return skey, vkey, nil
+}
+
+// NewECDSASigner creates a new note signer which produces ECDSA signatures
+// over SHA256 digests, as verified by NewECDSAVerifier.
+//
+// The key is expected to be provided as a string in the following form:
+//
+//	PRIVATE+KEY+<key_name>+<key_hash>+<key_bytes>
+//
+// Where <key_bytes> is a base64 encoded blob starting with a 0x02
+// (algECDSAWithSHA256) byte and followed by the PKCS #8 DER encoded private
+// key, and <key_hash> is the key hash of the corresponding verifier key.
+// Such keys are created by GenerateECDSAKey.
+func NewECDSASigner(skey string) (Signer, error) {
+	parts := strings.SplitN(skey, "+", 5)
+	if got, want := len(parts), 5; got != want {
+		return nil, fmt.Errorf("%w: key has %d parts, expected %d", ErrSignerID, got, want)
+	}
+	if parts[0] != "PRIVATE" || parts[1] != "KEY" || !isValidName(parts[2]) {
+		return nil, ErrSignerID
+	}
+	keyBytes, err := base64.StdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("%w: key has invalid base64: %v", ErrSignerID, err)
+	}
+	if len(keyBytes) < 2 {
+		return nil, fmt.Errorf("%w: key bytes too short", ErrSignerID)
+	}
+	if keyBytes[0] != algECDSAWithSHA256 {
+		return nil, fmt.Errorf("%w: key has incorrect type %d", ErrSignerAlg, keyBytes[0])
+	}
+	k, err := x509.ParsePKCS8PrivateKey(keyBytes[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: couldn't parse private key: %v", ErrSignerID, err)
+	}
+	ecdsaKey, ok := k.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: key is a %T, expected an ECDSA key", ErrSignerAlg, k)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	kh := keyHashECDSA(der)
+	khProvided, err := strconv.ParseUint(parts[3], 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: couldn't parse keyhash: %v", ErrSignerID, err)
+	}
+	if uint32(khProvided) != kh {
+		return nil, fmt.Errorf("%w: got %x, expected %x", ErrInvalidHash, khProvided, kh)
+	}
+
+	return &signer{
+		name: parts[2],
+		hash: kh,
+		sign: func(msg []byte) ([]byte, error) {
+			dgst := sha256.Sum256(msg)
+			return ecdsa.SignASN1(rand.Reader, ecdsaKey, dgst[:])
+		},
+		verify: func(msg, sig []byte) bool {
+			dgst := sha256.Sum256(msg)
+			return ecdsa.VerifyASN1(&ecdsaKey.PublicKey, dgst[:], sig)
+		},
+	}, nil
+}
+
 func keyHashECDSA(i []byte) uint32 {
 	h := sha256.Sum256(i)
 	return binary.BigEndian.Uint32(h[:])
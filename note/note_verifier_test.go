@@ -15,6 +15,12 @@
 package note
 
 import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"golang.org/x/mod/sumdb/note"
@@ -97,9 +103,10 @@ func TestNewVerifier(t *testing.T) {
 
 func TestNewECDSAVerifier(t *testing.T) {
 	for _, test := range []struct {
-		name    string
-		pubK    string
-		wantErr bool
+		name      string
+		pubK      string
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "sigStore works",
@@ -108,25 +115,30 @@ func TestNewECDSAVerifier(t *testing.T) {
 			name: "pixel works",
 			pubK: pixelKey,
 		}, {
-			name:    "wrong number of parts",
-			pubK:    "bananas.sigstore.dev+12344556",
-			wantErr: true,
+			name:      "wrong number of parts",
+			pubK:      "bananas.sigstore.dev+12344556",
+			wantErr:   true,
+			wantErrIs: ErrVerifierID,
 		}, {
-			name:    "invalid base64",
-			pubK:    "rekor.sigstore.dev+12345678+THIS_IS_NOT_BASE64!",
-			wantErr: true,
+			name:      "invalid base64",
+			pubK:      "rekor.sigstore.dev+12345678+THIS_IS_NOT_BASE64!",
+			wantErr:   true,
+			wantErrIs: ErrVerifierID,
 		}, {
-			name:    "invalid algo",
-			pubK:    "rekor.sigstore.dev+12345678+AwEB",
-			wantErr: true,
+			name:      "invalid algo",
+			pubK:      "rekor.sigstore.dev+12345678+AwEB",
+			wantErr:   true,
+			wantErrIs: ErrVerifierAlg,
 		}, {
-			name:    "invalid keyhash",
-			pubK:    "rekor.sigstore.dev+NOT_A_NUMBER+" + sigStoreKeyMaterial,
-			wantErr: true,
+			name:      "invalid keyhash",
+			pubK:      "rekor.sigstore.dev+NOT_A_NUMBER+" + sigStoreKeyMaterial,
+			wantErr:   true,
+			wantErrIs: ErrVerifierID,
 		}, {
-			name:    "incorrect keyhash",
-			pubK:    "rekor.sigstore.dev" + "+" + "00000000" + "+" + sigStoreKeyMaterial,
-			wantErr: true,
+			name:      "incorrect keyhash",
+			pubK:      "rekor.sigstore.dev" + "+" + "00000000" + "+" + sigStoreKeyMaterial,
+			wantErr:   true,
+			wantErrIs: ErrInvalidHash,
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
@@ -134,6 +146,9 @@ func TestNewECDSAVerifier(t *testing.T) {
 			if gotErr := err != nil; gotErr != test.wantErr {
 				t.Fatalf("Failed to create new ECDSA verifier from %q: %v", test.pubK, err)
 			}
+			if test.wantErrIs != nil && !errors.Is(err, test.wantErrIs) {
+				t.Errorf("NewECDSAVerifier(%q) = %v, want %v", test.pubK, err, test.wantErrIs)
+			}
 		})
 	}
 }
@@ -176,3 +191,68 @@ func TestECDSAVerifier(t *testing.T) {
 		})
 	}
 }
+
+func TestECDSASigner(t *testing.T) {
+	skey, vkey, err := GenerateECDSAKey("example.com/log")
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey: %v", err)
+	}
+	s, err := NewECDSASigner(skey)
+	if err != nil {
+		t.Fatalf("NewECDSASigner: %v", err)
+	}
+	v, err := NewVerifier(vkey)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	if s.Name() != v.Name() || s.KeyHash() != v.KeyHash() {
+		t.Fatalf("signer is %s+%08x, verifier is %s+%08x", s.Name(), s.KeyHash(), v.Name(), v.KeyHash())
+	}
+	n, err := note.Sign(&note.Note{Text: "hello\n"}, s)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := note.Open(n, note.VerifierList(v)); err != nil {
+		t.Errorf("Open with verifier from vkey: %v", err)
+	}
+	if _, err := note.Open(n, note.VerifierList(s.Verifier())); err != nil {
+		t.Errorf("Open with signer's verifier: %v", err)
+	}
+
+	for _, test := range []struct {
+		name      string
+		skey      string
+		wantErrIs error
+	}{
+		{name: "not a private key", skey: vkey, wantErrIs: ErrSignerID},
+		{name: "wrong key hash", skey: strings.Replace(skey, fmt.Sprintf("+%08x+", s.KeyHash()), "+00000000+", 1), wantErrIs: ErrInvalidHash},
+		{name: "Ed25519 key", skey: "PRIVATE+KEY+logandmap+38581672+AXJ0FKWOcO2ch6WC8kP705Ed3Gxu7pVtZLhfHAQwp+FE", wantErrIs: ErrSignerAlg},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewECDSASigner(test.skey); !errors.Is(err, test.wantErrIs) {
+				t.Errorf("NewECDSASigner() = %v, want %v", err, test.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestECDSAVerifierString(t *testing.T) {
+	der, err := base64.StdEncoding.DecodeString(sigStoreKeyMaterial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubK, err := x509.ParsePKIXPublicKey(der[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ECDSAVerifierString("rekor.sigstore.dev", pubK)
+	if err != nil {
+		t.Fatalf("ECDSAVerifierString: %v", err)
+	}
+	if got != sigStoreKey {
+		t.Errorf("ECDSAVerifierString() = %q, want %q", got, sigStoreKey)
+	}
+	if _, err := ECDSAVerifierString("rekor.sigstore.dev", ed25519.PublicKey(make([]byte, ed25519.PublicKeySize))); !errors.Is(err, ErrVerifierAlg) {
+		t.Errorf("ECDSAVerifierString with Ed25519 key = %v, want %v", err, ErrVerifierAlg)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proof
+
+import (
+	"fmt"
+
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// SubtreeInclusionProof proves that an entry of a log is included in a
+// subtree of it, as used by Merkle Tree Certificates where a certificate
+// carries such a proof along with cosignatures over the subtree.
+type SubtreeInclusionProof struct {
+	// Index is the index of the entry in the log, rather than in the subtree.
+	Index uint64
+	// Hashes is the inclusion proof of the entry in the tree made up of the
+	// entries of the subtree.
+	Hashes [][]byte
+}
+
+// Verify checks that the signed subtree is of the log with the given origin
+// and carries valid cosignatures from the provided verifiers, as described by
+// note.SignedSubtree.Verify, and that the proof shows the leaf with the given
+// hash to be at the proof's index in the subtree.
+//
+// The verified cosignatures are returned so that callers may make further
+// checks, such as against a witness policy.
+func (p SubtreeInclusionProof) Verify(alg log.HashAlgorithm, leafHash []byte, st f_note.SignedSubtree, origin string, verifiers ...f_note.SubtreeVerifier) ([]f_note.SubtreeSignature, error) {
+	if st.Origin != origin {
+		return nil, fmt.Errorf("subtree is of log %q, want %q", st.Origin, origin)
+	}
+	sigs, err := st.Verify(verifiers...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signed subtree: %w", err)
+	}
+	if err := log.VerifySubtreeInclusion(alg, st.Start, st.End, p.Index, leafHash, p.Hashes, st.Hash); err != nil {
+		return nil, fmt.Errorf("entry %d not included in subtree [%d, %d): %w", p.Index, st.Start, st.End, err)
+	}
+	return sigs, nil
+}
+
+// SubtreeConsistencyProof proves that a subtree of a log is part of the tree
+// committed to by a checkpoint.
+type SubtreeConsistencyProof struct {
+	// Hashes is the subtree consistency proof, as defined for Merkle Tree
+	// Certificates. For a subtree starting at the beginning of the log, this
+	// is the consistency proof of RFC 9162.
+	Hashes [][]byte
+	// Checkpoint is the signed note as described in https://c2sp.org/tlog-checkpoint
+	Checkpoint []byte
+}
+
+// Verify checks that the proof's checkpoint is signed by the log identified by
+// origin and logVerifier, and that the proof shows the subtree to be part of
+// that checkpoint's tree.
+//
+// Signatures from otherVerifiers, such as witnesses, are checked as described
+// by log.ParseCheckpoint. The verified checkpoint is returned so that callers
+// may make further checks, such as against a witness policy.
+func (p SubtreeConsistencyProof) Verify(alg log.HashAlgorithm, st f_note.Subtree, origin string, logVerifier note.Verifier, otherVerifiers ...note.Verifier) (*log.Checkpoint, error) {
+	if st.Origin != origin {
+		return nil, fmt.Errorf("subtree is of log %q, want %q", st.Origin, origin)
+	}
+	cp, _, _, err := log.ParseCheckpoint(p.Checkpoint, origin, logVerifier, otherVerifiers...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if err := log.VerifySubtreeConsistency(alg, st.Start, st.End, cp.Size, p.Hashes, st.Hash, cp.Hash); err != nil {
+		return nil, fmt.Errorf("subtree [%d, %d) not consistent with tree of size %d: %w", st.Start, st.End, cp.Size, err)
+	}
+	return cp, nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proof
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/formats/internal/merkletest"
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+)
+
+const subtreeOrigin = "example.com/mtc-log"
+
+func mustMLDSASigner(t *testing.T, name string) f_note.SubtreeSigner {
+	t.Helper()
+	skey, _, err := f_note.GenerateMLDSAKey(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := f_note.NewMLDSASigner(skey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSubtreeInclusionProof(t *testing.T) {
+	leaves := merkletest.Leaves(20)
+	w1, w2 := mustMLDSASigner(t, "w1"), mustMLDSASigner(t, "w2")
+	st := f_note.SignedSubtree{Subtree: f_note.Subtree{Origin: subtreeOrigin, Start: 16, End: 20, Hash: merkletest.Root(log.SHA256, leaves[16:20])}}
+	if err := st.Sign(0, w1); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 16; i < 20; i++ {
+		p := SubtreeInclusionProof{Index: uint64(i), Hashes: merkletest.Inclusion(log.SHA256, i-16, leaves[16:20])}
+		sigs, err := p.Verify(log.SHA256, log.SHA256.HashLeaf(leaves[i]), st, subtreeOrigin, w1.Verifier(), w2.Verifier())
+		if err != nil {
+			t.Fatalf("Verify(%d): %v", i, err)
+		}
+		if len(sigs) != 1 || sigs[0].Name != "w1" {
+			t.Errorf("Verify(%d) = %v, want signature from w1", i, sigs)
+		}
+	}
+
+	p := SubtreeInclusionProof{Index: 17, Hashes: merkletest.Inclusion(log.SHA256, 1, leaves[16:20])}
+	leafHash := log.SHA256.HashLeaf(leaves[17])
+	for _, test := range []struct {
+		desc      string
+		p         SubtreeInclusionProof
+		leafHash  []byte
+		origin    string
+		verifiers []f_note.SubtreeVerifier
+		wantErr   error
+	}{
+		{desc: "wrong leaf", p: p, leafHash: log.SHA256.HashLeaf(leaves[18]), origin: subtreeOrigin, verifiers: []f_note.SubtreeVerifier{w1.Verifier()}, wantErr: log.ErrRootMismatch},
+		{desc: "index outside subtree", p: SubtreeInclusionProof{Index: 15, Hashes: p.Hashes}, leafHash: leafHash, origin: subtreeOrigin, verifiers: []f_note.SubtreeVerifier{w1.Verifier()}, wantErr: log.ErrMalformedProof},
+		{desc: "unknown signer", p: p, leafHash: leafHash, origin: subtreeOrigin, verifiers: []f_note.SubtreeVerifier{w2.Verifier()}, wantErr: f_note.ErrUnverifiedSubtree},
+		{desc: "wrong origin", p: p, leafHash: leafHash, origin: "other", verifiers: []f_note.SubtreeVerifier{w1.Verifier()}},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := test.p.Verify(log.SHA256, test.leafHash, st, test.origin, test.verifiers...)
+			if err == nil || (test.wantErr != nil && !errors.Is(err, test.wantErr)) {
+				t.Errorf("Verify() = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubtreeConsistencyProof(t *testing.T) {
+	logSkey, logVkey, err := note.GenerateKey(rand.Reader, subtreeOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logSigner, err := note.NewSigner(logSkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logVerifier, err := note.NewVerifier(logVkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaves := merkletest.Leaves(27)
+	cp := log.Checkpoint{Origin: subtreeOrigin, Size: uint64(len(leaves)), Hash: merkletest.Root(log.SHA256, leaves)}
+	signed, err := note.Sign(&note.Note{Text: string(cp.Marshal())}, logSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range [][2]int{{0, 27}, {0, 13}, {8, 16}, {16, 27}, {24, 26}, {26, 27}} {
+		start, end := r[0], r[1]
+		t.Run(fmt.Sprintf("[%d, %d)", start, end), func(t *testing.T) {
+			st := f_note.Subtree{Origin: subtreeOrigin, Start: uint64(start), End: uint64(end), Hash: merkletest.Root(log.SHA256, leaves[start:end])}
+			p := SubtreeConsistencyProof{Hashes: merkletest.SubtreeConsistency(log.SHA256, start, end, leaves, true), Checkpoint: signed}
+			got, err := p.Verify(log.SHA256, st, subtreeOrigin, logVerifier)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if got.Size != cp.Size {
+				t.Errorf("got checkpoint size %d, want %d", got.Size, cp.Size)
+			}
+
+			st.Hash = log.SHA256.HashLeaf([]byte("other"))
+			if end-start > 1 {
+				st.Hash = merkletest.Root(log.SHA256, leaves[start:end-1])
+			}
+			if _, err := p.Verify(log.SHA256, st, subtreeOrigin, logVerifier); !errors.Is(err, log.ErrRootMismatch) {
+				t.Errorf("Verify with wrong subtree hash = %v, want %v", err, log.ErrRootMismatch)
+			}
+		})
+	}
+
+	st := f_note.Subtree{Origin: subtreeOrigin, Start: 8, End: 16, Hash: merkletest.Root(log.SHA256, leaves[8:16])}
+	p := SubtreeConsistencyProof{Hashes: merkletest.SubtreeConsistency(log.SHA256, 8, 16, leaves, true), Checkpoint: signed}
+	if _, err := p.Verify(log.SHA256, st, "other", logVerifier); err == nil {
+		t.Error("Verify with wrong origin succeeded")
+	}
+	invalid := st
+	invalid.Start, invalid.End = 4, 12
+	if _, err := p.Verify(log.SHA256, invalid, subtreeOrigin, logVerifier); !errors.Is(err, log.ErrMalformedProof) {
+		t.Errorf("Verify with invalid subtree = %v, want %v", err, log.ErrMalformedProof)
+	}
+	p.Checkpoint = []byte(strings.Replace(string(signed), "27\n", "28\n", 1))
+	if _, err := p.Verify(log.SHA256, st, subtreeOrigin, logVerifier); err == nil || !strings.Contains(err.Error(), "invalid checkpoint") {
+		t.Errorf("Verify with tampered checkpoint = %v, want invalid checkpoint", err)
+	}
+}
+
+func mustHex(t *testing.T, hs ...string) [][]byte {
+	t.Helper()
+	r := make([][]byte, len(hs))
+	for i, h := range hs {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r[i] = b
+	}
+	return r
+}
+
+// TestSubtreeProofs_KnownAnswer checks fixed proofs over merkletest.Leaves(27),
+// which were computed independently of this module from the definitions in
+// RFC 9162 and the Merkle Tree Certificates draft.
+func TestSubtreeProofs_KnownAnswer(t *testing.T) {
+	root := mustHex(t, "dd43fa262693d3448038511a6f993fcaa6de12301d381bca7c3695630e8594bd")[0]
+	logSkey, logVkey, err := note.GenerateKey(rand.Reader, subtreeOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logSigner, err := note.NewSigner(logSkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logVerifier, err := note.NewVerifier(logVkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp := log.Checkpoint{Origin: subtreeOrigin, Size: 27, Hash: root}
+	signed, err := note.Sign(&note.Note{Text: string(cp.Marshal())}, logSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := mustMLDSASigner(t, "w1")
+	leaves := merkletest.Leaves(27)
+
+	for _, test := range []struct {
+		desc        string
+		start, end  uint64
+		hash        string
+		index       uint64
+		inclusion   []string
+		consistency []string
+	}{
+		{
+			desc:  "not left-aligned, not a power of two",
+			start: 16, end: 27,
+			hash:  "4b82fe51ca4b0c7d36e2d9d37ebe7a883ed47a699613047a1d4fbc972f206df7",
+			index: 21,
+			inclusion: []string{
+				"857d68f1999b574d329cd295b5bfc24e889bd4ea295b94283935d4d12f8c01fc",
+				"53f8ca462c0c6b7a787e662727887739ff63dcd6aecee676cff258151803fbcf",
+				"aff437423f22373bf5bb35d670c3f824f6dce8ee9da6d23b222b6f2ef01413e4",
+				"48e185cc1fd183f2252f67258af7f84e9ac81d8b8907879f4027e784fbc0613f",
+			},
+			consistency: []string{
+				"714a29298ca7d8643975100569499c2309e83a6f2f970902dc46ef32ebc1b00f",
+			},
+		}, {
+			desc:  "not left-aligned",
+			start: 8, end: 16,
+			hash:  "a800a9786c988ce79d32396a32d94a7eabce331b32aa4db237159bbf94c532fe",
+			index: 13,
+			inclusion: []string{
+				"095a4e75d5182c6b8a09d921272eafef0941c801d454275ed9d7398e44c3353e",
+				"43fffb0f3343f928b89f4c93045739473131aa8db7a16b40938ee7693e5bf3e7",
+				"fae26f92225ab432372dd5a7d48db45f73cb5ca36bd60bf1d554c0f81d6eb9ae",
+			},
+			consistency: []string{
+				"c5c2c820ed342fdda8ce896b6b9cf5b8c00a21cc4b20714cc6e5d3c05c35240b",
+				"4b82fe51ca4b0c7d36e2d9d37ebe7a883ed47a699613047a1d4fbc972f206df7",
+			},
+		}, {
+			desc:  "not a power of two",
+			start: 0, end: 13,
+			hash:  "cf9b4c2a44a7caa722d5d548ee64ff90a119b0c31acac3444dc583000a39d3ca",
+			index: 5,
+			inclusion: []string{
+				"83115f8947955fafdc2a27e7f4c0854bbd8da27bb1b3e3405db571c9af8dbe1a",
+				"6ebcc54b6710ee0610a7fc82cde51713db280e3dc84515bde9632a19b65a0b93",
+				"4f631084a157c54f54fcfb23ff5eb8650c4ba160c295bb13a9832b109d52677e",
+				"14a20345371e2cf4d6bb34adec274a9f95fc30f31508d70465a56f868b601975",
+			},
+			consistency: []string{
+				"095a4e75d5182c6b8a09d921272eafef0941c801d454275ed9d7398e44c3353e",
+				"76f44e2f645e433127a464de261726b26c9dd742d578dd222173aa51d3c4b0b4",
+				"43fffb0f3343f928b89f4c93045739473131aa8db7a16b40938ee7693e5bf3e7",
+				"fae26f92225ab432372dd5a7d48db45f73cb5ca36bd60bf1d554c0f81d6eb9ae",
+				"c5c2c820ed342fdda8ce896b6b9cf5b8c00a21cc4b20714cc6e5d3c05c35240b",
+				"4b82fe51ca4b0c7d36e2d9d37ebe7a883ed47a699613047a1d4fbc972f206df7",
+			},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			st := f_note.SignedSubtree{Subtree: f_note.Subtree{Origin: subtreeOrigin, Start: test.start, End: test.end, Hash: mustHex(t, test.hash)[0]}}
+			if err := st.Sign(0, w); err != nil {
+				t.Fatal(err)
+			}
+
+			ip := SubtreeInclusionProof{Index: test.index, Hashes: mustHex(t, test.inclusion...)}
+			if _, err := ip.Verify(log.SHA256, log.SHA256.HashLeaf(leaves[test.index]), st, subtreeOrigin, w.Verifier()); err != nil {
+				t.Errorf("SubtreeInclusionProof.Verify: %v", err)
+			}
+			cp := SubtreeConsistencyProof{Hashes: mustHex(t, test.consistency...), Checkpoint: signed}
+			if _, err := cp.Verify(log.SHA256, st.Subtree, subtreeOrigin, logVerifier); err != nil {
+				t.Errorf("SubtreeConsistencyProof.Verify: %v", err)
+			}
+		})
+	}
+}
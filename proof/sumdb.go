@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proof
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+// SumDBLookupToTLogProof converts a response from the /lookup endpoint of a
+// Go checksum database into a TLogProof.
+//
+// A lookup response is made up of the record ID, the record text, and the
+// signed tree note, as described in https://go.dev/design/25530-sumdb.
+// The signed tree note is verified using the provided origin and log verifier,
+// and only then are tiles read from the provided TileReader in order to build
+// an inclusion proof for the record.
+//
+// The ExtraData of the returned proof holds the decimal record ID.
+func SumDBLookupToTLogProof(lookup []byte, origin string, logVerifier note.Verifier, tiles tlog.TileReader) (*TLogProof, error) {
+	id, text, signed, err := tlog.ParseRecord(lookup)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sumdb lookup response: %w", err)
+	}
+	tree, err := sumDBTree(signed, origin, logVerifier)
+	if err != nil {
+		return nil, err
+	}
+	if id >= tree.N {
+		return nil, fmt.Errorf("sumdb record %d is not in tree of size %d", id, tree.N)
+	}
+
+	// TileHashReader authenticates every tile it reads against the tree hash,
+	// so the proof below is built only from hashes which are in the tree.
+	p, err := tlog.ProveRecord(tree.N, id, tlog.TileHashReader(tree, tiles))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build inclusion proof for sumdb record %d: %w", id, err)
+	}
+	if err := tlog.CheckRecord(p, tree.N, tree.Hash, id, tlog.RecordHash(text)); err != nil {
+		return nil, fmt.Errorf("sumdb record %d not included in tree: %w", id, err)
+	}
+
+	hashes := make([][sha256.Size]byte, len(p))
+	for i, h := range p {
+		hashes[i] = h
+	}
+	return &TLogProof{
+		Index:      uint64(id),
+		Hashes:     hashes,
+		Checkpoint: signed,
+		ExtraData:  []byte(strconv.FormatInt(id, 10)),
+	}, nil
+}
+
+// VerifySumDBRecord checks that the provided TLogProof, as created by
+// SumDBLookupToTLogProof, proves the inclusion of the given record text in
+// the Go checksum database identified by the origin and log verifier.
+//
+// The record hash is computed as an RFC 6962 leaf hash over the record text.
+func VerifySumDBRecord(p TLogProof, record []byte, origin string, logVerifier note.Verifier) error {
+	id, err := strconv.ParseInt(string(p.ExtraData), 10, 64)
+	if err != nil {
+		return fmt.Errorf("tlog proof extra data is not a sumdb record ID: %w", err)
+	}
+	if p.Index > math.MaxInt64 || id != int64(p.Index) {
+		return fmt.Errorf("%w: sumdb record ID %d does not match proof index %d", log.ErrMalformedProof, id, p.Index)
+	}
+	tree, err := sumDBTree(p.Checkpoint, origin, logVerifier)
+	if err != nil {
+		return err
+	}
+	if len(p.OtherHashes) > 0 {
+		return fmt.Errorf("%w: sumdb proofs must have SHA-256 hashes", log.ErrMalformedProof)
+	}
+	rp := make(tlog.RecordProof, len(p.Hashes))
+	for i, h := range p.Hashes {
+		rp[i] = h
+	}
+	if err := tlog.CheckRecord(rp, tree.N, tree.Hash, id, tlog.RecordHash(record)); err != nil {
+		return fmt.Errorf("%w: sumdb record %d not included in tree: %w", log.ErrRootMismatch, id, err)
+	}
+	return nil
+}
+
+// sumDBTree verifies the provided signed tree note and returns the tree it
+// commits to.
+func sumDBTree(signed []byte, origin string, logVerifier note.Verifier) (tlog.Tree, error) {
+	tree, err := checkpointTree(signed, origin, logVerifier)
+	if err != nil {
+		return tlog.Tree{}, fmt.Errorf("invalid sumdb tree note: %w", err)
+	}
+	return tree, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proof
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+const (
+	// The testdata/sumdb directory holds the tiles for a 300 entry sumdb-style
+	// log, along with a checkpoint signed by this key and lookup responses for
+	// a couple of records.
+	sumDBOrigin = "go.sum database tree"
+	sumDBVKey   = "sumdb.example.com+40d6908f+AbcyZPdP5c9a3F26SC02aeGyIhwtk1ZHtVHskJQ/Qcz6"
+	sumDBDir    = "testdata/sumdb"
+)
+
+// dirTileReader is a tlog.TileReader which reads tiles from a local directory.
+type dirTileReader string
+
+func (d dirTileReader) Height() int { return 8 }
+
+func (d dirTileReader) ReadTiles(tiles []tlog.Tile) ([][]byte, error) {
+	data := make([][]byte, len(tiles))
+	for i, t := range tiles {
+		b, err := os.ReadFile(filepath.Join(string(d), t.Path()))
+		if err != nil {
+			return nil, err
+		}
+		data[i] = b
+	}
+	return data, nil
+}
+
+func (d dirTileReader) SaveTiles([]tlog.Tile, [][]byte) {}
+
+func readSumDBLookup(t *testing.T, id string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(sumDBDir, "lookup", id))
+	if err != nil {
+		t.Fatalf("Failed to read lookup fixture: %v", err)
+	}
+	return b
+}
+
+func TestSumDBLookupToTLogProof(t *testing.T) {
+	v, err := note.NewVerifier(sumDBVKey)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	for _, test := range []struct {
+		id        string
+		wantIndex uint64
+	}{
+		{id: "7", wantIndex: 7},
+		{id: "299", wantIndex: 299},
+	} {
+		t.Run(test.id, func(t *testing.T) {
+			lookup := readSumDBLookup(t, test.id)
+			p, err := SumDBLookupToTLogProof(lookup, sumDBOrigin, v, dirTileReader(sumDBDir))
+			if err != nil {
+				t.Fatalf("SumDBLookupToTLogProof: %v", err)
+			}
+			if p.Index != test.wantIndex {
+				t.Errorf("got index %d, want %d", p.Index, test.wantIndex)
+			}
+			if got := string(p.ExtraData); got != test.id {
+				t.Errorf("got extra data %q, want %q", got, test.id)
+			}
+
+			// The proof must survive a round trip through the tlog-proof encoding.
+			var got TLogProof
+			if err := got.Unmarshal(p.Marshal()); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			_, record, _, err := tlog.ParseRecord(lookup)
+			if err != nil {
+				t.Fatalf("ParseRecord: %v", err)
+			}
+			if err := VerifySumDBRecord(got, record, sumDBOrigin, v); err != nil {
+				t.Errorf("VerifySumDBRecord: %v", err)
+			}
+			if err := VerifySumDBRecord(got, append(record, "example.com/evil v1.0.0 h1:bad=\n"...), sumDBOrigin, v); err == nil {
+				t.Error("VerifySumDBRecord succeeded with modified record")
+			}
+		})
+	}
+}
+
+func TestSumDBLookupToTLogProof_Errors(t *testing.T) {
+	v, err := note.NewVerifier(sumDBVKey)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	otherV, err := note.NewVerifier("sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8")
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	lookup := readSumDBLookup(t, "7")
+
+	for _, test := range []struct {
+		desc          string
+		lookup        []byte
+		origin        string
+		verifier      note.Verifier
+		tiles         tlog.TileReader
+		wantErrSubstr string
+	}{
+		{
+			desc:          "malformed lookup",
+			lookup:        []byte("not a record\n"),
+			origin:        sumDBOrigin,
+			verifier:      v,
+			tiles:         dirTileReader(sumDBDir),
+			wantErrSubstr: "invalid sumdb lookup response",
+		}, {
+			desc:          "wrong origin",
+			lookup:        lookup,
+			origin:        "some other tree",
+			verifier:      v,
+			tiles:         dirTileReader(sumDBDir),
+			wantErrSubstr: "invalid sumdb tree note",
+		}, {
+			desc:          "wrong verifier",
+			lookup:        lookup,
+			origin:        sumDBOrigin,
+			verifier:      otherV,
+			tiles:         dirTileReader(sumDBDir),
+			wantErrSubstr: "invalid sumdb tree note",
+		}, {
+			desc:          "record not in tree",
+			lookup:        bytes.Replace(lookup, []byte("7\n"), []byte("300\n"), 1),
+			origin:        sumDBOrigin,
+			verifier:      v,
+			tiles:         dirTileReader(sumDBDir),
+			wantErrSubstr: "is not in tree",
+		}, {
+			desc:          "wrong record text",
+			lookup:        bytes.Replace(lookup, []byte("mod7 "), []byte("mod8 "), 1),
+			origin:        sumDBOrigin,
+			verifier:      v,
+			tiles:         dirTileReader(sumDBDir),
+			wantErrSubstr: "not included in tree",
+		}, {
+			desc:          "missing tiles",
+			lookup:        lookup,
+			origin:        sumDBOrigin,
+			verifier:      v,
+			tiles:         dirTileReader(t.TempDir()),
+			wantErrSubstr: "failed to build inclusion proof",
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := SumDBLookupToTLogProof(test.lookup, test.origin, test.verifier, test.tiles)
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !strings.Contains(err.Error(), test.wantErrSubstr) {
+				t.Errorf("error message doesn't contain %q, got: %v", test.wantErrSubstr, err)
+			}
+		})
+	}
+}
+
+func TestVerifySumDBRecord_Errors(t *testing.T) {
+	v, err := note.NewVerifier(sumDBVKey)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	lookup := readSumDBLookup(t, "7")
+	_, record, _, err := tlog.ParseRecord(lookup)
+	if err != nil {
+		t.Fatalf("ParseRecord: %v", err)
+	}
+	p, err := SumDBLookupToTLogProof(lookup, sumDBOrigin, v, dirTileReader(sumDBDir))
+	if err != nil {
+		t.Fatalf("SumDBLookupToTLogProof: %v", err)
+	}
+
+	for _, test := range []struct {
+		desc          string
+		modify        func(p *TLogProof)
+		wantErrSubstr string
+	}{
+		{
+			desc:          "extra data not a record ID",
+			modify:        func(p *TLogProof) { p.ExtraData = []byte("seven") },
+			wantErrSubstr: "not a sumdb record ID",
+		}, {
+			desc:          "record ID does not match index",
+			modify:        func(p *TLogProof) { p.Index = 8 },
+			wantErrSubstr: "does not match proof index",
+		}, {
+			desc:          "missing hashes",
+			modify:        func(p *TLogProof) { p.Hashes = p.Hashes[1:] },
+			wantErrSubstr: "not included in tree",
+		}, {
+			desc:          "unsigned checkpoint",
+			modify:        func(p *TLogProof) { p.Checkpoint = []byte("go.sum database tree\n300\n") },
+			wantErrSubstr: "invalid sumdb tree note",
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			p := *p
+			test.modify(&p)
+			err := VerifySumDBRecord(p, record, sumDBOrigin, v)
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !strings.Contains(err.Error(), test.wantErrSubstr) {
+				t.Errorf("error message doesn't contain %q, got: %v", test.wantErrSubstr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proof
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+// NewTLogProofFromTiles builds a TLogProof for the entry at the given index in
+// the tree committed to by the signed checkpoint, reading the hashes it needs
+// from the provided TileReader.
+//
+// The checkpoint is verified using the provided origin and log verifier, and
+// only then are tiles read. Every tile is authenticated against the
+// checkpoint's root hash, so the proof is built only from hashes which are in
+// the tree. As tiles are always SHA-256, the log must use SHA-256 too.
+func NewTLogProofFromTiles(signed []byte, origin string, logVerifier note.Verifier, index uint64, tiles tlog.TileReader) (*TLogProof, error) {
+	tree, err := checkpointTree(signed, origin, logVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if index >= uint64(tree.N) {
+		return nil, fmt.Errorf("entry %d is not in tree of size %d", index, tree.N)
+	}
+	p, err := tlog.ProveRecord(tree.N, int64(index), tlog.TileHashReader(tree, tiles))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build inclusion proof for entry %d: %w", index, err)
+	}
+	hashes := make([][sha256.Size]byte, len(p))
+	for i, h := range p {
+		hashes[i] = h
+	}
+	return &TLogProof{
+		Index:      index,
+		Hashes:     hashes,
+		Checkpoint: signed,
+	}, nil
+}
+
+// checkpointTree verifies the provided signed checkpoint and returns the
+// SHA-256 tree it commits to.
+func checkpointTree(signed []byte, origin string, logVerifier note.Verifier) (tlog.Tree, error) {
+	cp, _, _, err := log.ParseCheckpoint(signed, origin, logVerifier)
+	if err != nil {
+		return tlog.Tree{}, err
+	}
+	if cp.Size > math.MaxInt64 {
+		return tlog.Tree{}, fmt.Errorf("tree size %d too large", cp.Size)
+	}
+	if len(cp.Hash) != tlog.HashSize {
+		return tlog.Tree{}, fmt.Errorf("tree hash length was %d, expected %d", len(cp.Hash), tlog.HashSize)
+	}
+	return tlog.Tree{N: int64(cp.Size), Hash: tlog.Hash(cp.Hash)}, nil
+}
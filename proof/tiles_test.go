@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proof
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+func TestNewTLogProofFromTiles(t *testing.T) {
+	v, err := note.NewVerifier(sumDBVKey)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	signed, err := os.ReadFile(filepath.Join(sumDBDir, "checkpoint"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, id := range []string{"7", "299"} {
+		t.Run(id, func(t *testing.T) {
+			index, record, _, err := tlog.ParseRecord(readSumDBLookup(t, id))
+			if err != nil {
+				t.Fatalf("ParseRecord: %v", err)
+			}
+			p, err := NewTLogProofFromTiles(signed, sumDBOrigin, v, uint64(index), dirTileReader(sumDBDir))
+			if err != nil {
+				t.Fatalf("NewTLogProofFromTiles: %v", err)
+			}
+			if p.Index != uint64(index) {
+				t.Errorf("got index %d, want %d", p.Index, index)
+			}
+
+			leafHash := log.SHA256.HashLeaf(record)
+			cp, err := p.Verify(log.SHA256, leafHash, sumDBOrigin, v)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if cp.Size != 300 {
+				t.Errorf("got checkpoint size %d, want 300", cp.Size)
+			}
+			if _, err := p.Verify(log.SHA256, log.SHA256.HashLeaf([]byte("other")), sumDBOrigin, v); !errors.Is(err, log.ErrRootMismatch) {
+				t.Errorf("Verify with wrong leaf = %v, want %v", err, log.ErrRootMismatch)
+			}
+		})
+	}
+}
+
+func TestNewTLogProofFromTiles_Errors(t *testing.T) {
+	v, err := note.NewVerifier(sumDBVKey)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	signed, err := os.ReadFile(filepath.Join(sumDBDir, "checkpoint"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	for _, test := range []struct {
+		desc          string
+		origin        string
+		index         uint64
+		tiles         tlog.TileReader
+		wantErrSubstr string
+	}{
+		{
+			desc:          "wrong origin",
+			origin:        "some other tree",
+			tiles:         dirTileReader(sumDBDir),
+			wantErrSubstr: "invalid checkpoint",
+		}, {
+			desc:          "entry not in tree",
+			origin:        sumDBOrigin,
+			index:         300,
+			tiles:         dirTileReader(sumDBDir),
+			wantErrSubstr: "is not in tree",
+		}, {
+			desc:          "missing tiles",
+			origin:        sumDBOrigin,
+			tiles:         dirTileReader(t.TempDir()),
+			wantErrSubstr: "failed to build inclusion proof",
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := NewTLogProofFromTiles(signed, test.origin, v, test.index, test.tiles)
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !strings.Contains(err.Error(), test.wantErrSubstr) {
+				t.Errorf("error message doesn't contain %q, got: %v", test.wantErrSubstr, err)
+			}
+		})
+	}
+}
@@ -19,27 +19,59 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
 )
 
 const (
 	tlogProofHeaderV1 = "c2sp.org/tlog-proof@v1"
 )
 
+// ErrMalformedTLogProof matches, using errors.Is, every error returned
+// because tlog-proof data could not be parsed. Such errors are of type
+// *ParseError.
+var ErrMalformedTLogProof = errors.New("malformed tlog proof")
+
+// ParseError describes why tlog-proof data could not be parsed.
+//
+// errors.Is reports a ParseError as matching ErrMalformedTLogProof, in
+// addition to the error it wraps.
+type ParseError struct {
+	// Line is the 1-based line number of the data which is at fault.
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string { return fmt.Sprintf("%v (line %d)", e.Err, e.Line) }
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrMalformedTLogProof.
+func (e *ParseError) Is(target error) bool { return target == ErrMalformedTLogProof }
+
 // TLogProof represents a transparency log proof as described in https://c2sp.org/tlog-proof
 type TLogProof struct {
 	// Index is the index of an entry in the log
 	Index uint64
 	// Hashes is the Merkle inclusion proof as described in https://www.rfc-editor.org/rfc/rfc6962.html#section-2.1.1
+	// for logs whose hash algorithm produces 32-byte hashes, such as SHA-256.
 	Hashes [][sha256.Size]byte
+	// OtherHashes is used in place of Hashes for logs whose hash algorithm
+	// produces hashes of another size, such as SHA-384. At most one of Hashes
+	// and OtherHashes should be set.
+	OtherHashes [][]byte
 	// Checkpoint is the signed note as described in https://c2sp.org/tlog-checkpoint
 	Checkpoint []byte
 	// ExtraData contains optional application-specific data
 	ExtraData []byte
 }
 
+// Marshal returns the tlog-proof encoding of this TLogProof.
 func (p TLogProof) Marshal() []byte {
 	var proof bytes.Buffer
 	fmt.Fprintf(&proof, "%s\n", tlogProofHeaderV1)
@@ -48,56 +80,107 @@ func (p TLogProof) Marshal() []byte {
 		fmt.Fprintf(&proof, "%s\n", base64.StdEncoding.EncodeToString(p.ExtraData))
 	}
 	fmt.Fprintf(&proof, "index %d\n", p.Index)
-	for _, h := range p.Hashes {
-		fmt.Fprintf(&proof, "%s\n", base64.StdEncoding.EncodeToString(h[:]))
+	for _, h := range p.ProofHashes() {
+		fmt.Fprintf(&proof, "%s\n", base64.StdEncoding.EncodeToString(h))
 	}
 	proof.WriteByte('\n')
 	proof.Write(p.Checkpoint)
 	return proof.Bytes()
 }
 
+// ProofHashes returns the hashes of the inclusion proof, whether they are held
+// in Hashes or OtherHashes.
+func (p TLogProof) ProofHashes() [][]byte {
+	hashes := make([][]byte, 0, len(p.Hashes)+len(p.OtherHashes))
+	for _, h := range p.Hashes {
+		hashes = append(hashes, h[:])
+	}
+	return append(hashes, p.OtherHashes...)
+}
+
+// Verify checks that the proof's checkpoint is signed by the log identified by
+// origin and logVerifier, and that the proof shows the leaf with the given hash
+// to be at the proof's index in that checkpoint's tree.
+//
+// Signatures from otherVerifiers, such as witnesses, are checked as described
+// by log.ParseCheckpoint. The verified checkpoint is returned so that callers
+// may make further checks, such as against a witness policy.
+func (p TLogProof) Verify(alg log.HashAlgorithm, leafHash []byte, origin string, logVerifier note.Verifier, otherVerifiers ...note.Verifier) (*log.Checkpoint, error) {
+	cp, _, _, err := log.ParseCheckpoint(p.Checkpoint, origin, logVerifier, otherVerifiers...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if err := log.VerifyInclusion(alg, p.Index, cp.Size, leafHash, p.ProofHashes(), cp.Hash); err != nil {
+		return nil, fmt.Errorf("entry %d not included in tree of size %d: %w", p.Index, cp.Size, err)
+	}
+	return cp, nil
+}
+
+// Unmarshal parses the tlog-proof encoded data and stores the result in the
+// TLogProof, requiring that all hashes are SHA-256 sized.
 func (p *TLogProof) Unmarshal(data []byte) error {
+	return p.UnmarshalWithHash(data, log.SHA256)
+}
+
+// UnmarshalWithHash behaves like Unmarshal, but requires that all hashes are
+// the size of those produced by the provided hash algorithm. The hashes are
+// stored in Hashes if they are 32 bytes long, and in OtherHashes otherwise.
+//
+// Errors due to malformed data are of type *ParseError, and match
+// ErrMalformedTLogProof.
+func (p *TLogProof) UnmarshalWithHash(data []byte, alg log.HashAlgorithm) error {
+	if alg.Size() == 0 {
+		return fmt.Errorf("%w %v", log.ErrUnknownHashAlgorithm, alg)
+	}
 	var err error
 	b := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	scan := func() bool {
+		line++
+		return b.Scan()
+	}
+	parseErr := func(format string, args ...any) error {
+		return &ParseError{Line: line, Err: fmt.Errorf(format, args...)}
+	}
 
-	if b.Scan(); b.Text() != tlogProofHeaderV1 {
-		return fmt.Errorf("tlog proof missing expected header")
+	if scan(); b.Text() != tlogProofHeaderV1 {
+		return parseErr("tlog proof missing expected header")
 	}
 
 	// Handle optional extra line
 	var extra []byte
-	if b.Scan(); strings.HasPrefix(b.Text(), "extra ") {
+	if scan(); strings.HasPrefix(b.Text(), "extra ") {
 		e, _ := strings.CutPrefix(b.Text(), "extra ")
 		extra, err = base64.StdEncoding.DecodeString(e)
 		if err != nil {
-			return fmt.Errorf("tlog proof extra data not base64 encoded: %w", err)
+			return parseErr("tlog proof extra data not base64 encoded: %w", err)
 		}
-		b.Scan()
+		scan()
 	}
 
 	var idx uint64
 	idxStr, ok := strings.CutPrefix(b.Text(), "index ")
 	if !ok {
-		return fmt.Errorf("tlog proof missing required index")
+		return parseErr("tlog proof missing required index")
 	}
 	idx, err = strconv.ParseUint(idxStr, 10, 64)
 	if err != nil {
-		return fmt.Errorf("tlog proof index not a valid uint64: %w", err)
+		return parseErr("tlog proof index not a valid uint64: %w", err)
 	}
 
-	var hashes [][sha256.Size]byte
-	for b.Scan() {
+	var hashes [][]byte
+	for scan() {
 		if b.Text() == "" {
 			break
 		}
 		hash, err := base64.StdEncoding.DecodeString(b.Text())
 		if err != nil {
-			return fmt.Errorf("tlog proof hash not base64 encoded: %w", err)
+			return parseErr("tlog proof hash not base64 encoded: %w", err)
 		}
-		if len(hash) != sha256.Size {
-			return fmt.Errorf("tlog proof hash length was %d, expected %d", len(hash), sha256.Size)
+		if len(hash) != alg.Size() {
+			return parseErr("tlog proof hash length was %d, expected %d", len(hash), alg.Size())
 		}
-		hashes = append(hashes, [sha256.Size]byte(hash))
+		hashes = append(hashes, hash)
 	}
 
 	var checkpoint bytes.Buffer
@@ -111,7 +194,14 @@ func (p *TLogProof) Unmarshal(data []byte) error {
 	}
 
 	p.Index = idx
-	p.Hashes = hashes
+	p.Hashes, p.OtherHashes = nil, nil
+	if alg.Size() == sha256.Size {
+		for _, h := range hashes {
+			p.Hashes = append(p.Hashes, [sha256.Size]byte(h))
+		}
+	} else {
+		p.OtherHashes = hashes
+	}
 	p.Checkpoint = checkpoint.Bytes()
 	p.ExtraData = extra
 
@@ -18,9 +18,12 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/transparency-dev/formats/log"
 )
 
 func TestMarshal(t *testing.T) {
@@ -80,42 +83,52 @@ func TestUnmarshalErrors(t *testing.T) {
 		name          string
 		proof         []byte
 		wantErrSubstr string
+		// wantLine is the line reported by a *ParseError, or 0 if the error
+		// is not due to malformed data.
+		wantLine int
 	}{
 		{
 			name:          "missing header",
 			proof:         []byte("wrong-header\nindex 0\n\ncheckpoint\n"),
 			wantErrSubstr: "missing expected header",
+			wantLine:      1,
 		},
 		{
 			name:          "invalid extra data encoding",
 			proof:         []byte("c2sp.org/tlog-proof@v1\nextra !!notbase64!!\nindex 0\n\ncheckpoint\n"),
 			wantErrSubstr: "extra data not base64 encoded",
+			wantLine:      2,
 		},
 		{
 			name:          "missing index",
 			proof:         []byte("c2sp.org/tlog-proof@v1\n\n\ncheckpoint\n"),
 			wantErrSubstr: "missing required index",
+			wantLine:      2,
 		},
 		{
 			name:          "invalid index - not a number",
 			proof:         []byte("c2sp.org/tlog-proof@v1\nindex notanumber\n\ncheckpoint\n"),
 			wantErrSubstr: "not a valid uint64",
+			wantLine:      2,
 		},
 		{
 			name:          "invalid index - negative",
 			proof:         []byte("c2sp.org/tlog-proof@v1\nindex -5\n\ncheckpoint\n"),
 			wantErrSubstr: "not a valid uint64",
+			wantLine:      2,
 		},
 		{
 			name:          "invalid hash base64",
 			proof:         []byte("c2sp.org/tlog-proof@v1\nindex 0\n!!notbase64!!\n\ncheckpoint\n"),
 			wantErrSubstr: "hash not base64 encoded",
+			wantLine:      3,
 		},
 		{
 			name: "incorrect hash length",
 			proof: []byte("c2sp.org/tlog-proof@v1\nindex 0\n" +
 				base64.StdEncoding.EncodeToString(make([]byte, 64)) + "\n\ncheckpoint\n"),
 			wantErrSubstr: "hash length",
+			wantLine:      3,
 		},
 		{
 			name:          "scanner error - buffer too large",
@@ -136,6 +149,17 @@ func TestUnmarshalErrors(t *testing.T) {
 			if !strings.Contains(err.Error(), tt.wantErrSubstr) {
 				t.Errorf("error message doesn't contain %q, got: %v", tt.wantErrSubstr, err)
 			}
+
+			var pErr *ParseError
+			if gotMalformed := errors.As(err, &pErr); gotMalformed != (tt.wantLine > 0) {
+				t.Fatalf("got error %v, want *ParseError: %t", err, tt.wantLine > 0)
+			}
+			if pErr != nil && pErr.Line != tt.wantLine {
+				t.Errorf("got error on line %d, want %d", pErr.Line, tt.wantLine)
+			}
+			if pErr != nil && !errors.Is(err, ErrMalformedTLogProof) {
+				t.Errorf("error %v does not match ErrMalformedTLogProof", err)
+			}
 		})
 	}
 }
@@ -201,3 +225,25 @@ func TestRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalWithHash(t *testing.T) {
+	h := log.SHA384.HashLeaf([]byte("a"))
+	want := TLogProof{
+		Index:       3,
+		OtherHashes: [][]byte{h, h},
+		Checkpoint:  []byte("checkpoint\n"),
+	}
+	var got TLogProof
+	if err := got.UnmarshalWithHash(want.Marshal(), log.SHA384); err != nil {
+		t.Fatalf("UnmarshalWithHash: %v", err)
+	}
+	if len(got.Hashes) != 0 || len(got.OtherHashes) != 2 || !bytes.Equal(got.OtherHashes[0], h) || !bytes.Equal(got.OtherHashes[1], h) {
+		t.Errorf("got hashes %x and %x, want only %x", got.Hashes, got.OtherHashes, want.OtherHashes)
+	}
+	if err := got.Unmarshal(want.Marshal()); err == nil || !strings.Contains(err.Error(), "hash length") {
+		t.Errorf("Unmarshal of SHA-384 hashes = %v, want hash length error", err)
+	}
+	if err := got.UnmarshalWithHash(want.Marshal(), log.HashAlgorithm(99)); err == nil {
+		t.Error("UnmarshalWithHash with unknown algorithm succeeded")
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 The Tessera authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+)
+
+const (
+	// maxAddCheckpointBody is the largest add-checkpoint request body accepted.
+	maxAddCheckpointBody = 1 << 20
+	// maxConsistencyProofHashes is the largest number of hashes a consistency
+	// proof for a tree of up to 2^64 leaves can contain.
+	maxConsistencyProofHashes = 63
+
+	contentTypeTLogSize = "text/x.tlog.size"
+)
+
+// KnownLogs provides the verifiers for the logs which a witness follows.
+type KnownLogs interface {
+	// Verifiers returns the verifiers trusted for checkpoints with the given
+	// origin, or nil if the log is unknown.
+	Verifiers(origin string) []note.Verifier
+}
+
+// LogVerifiers is a KnownLogs implementation which maps each log's origin to
+// the verifiers trusted for it.
+type LogVerifiers map[string][]note.Verifier
+
+// Verifiers implements KnownLogs.
+func (l LogVerifiers) Verifiers(origin string) []note.Verifier {
+	return l[origin]
+}
+
+// NewAddCheckpointHandler returns an http.Handler which implements the
+// add-checkpoint endpoint of a witness, as described by
+// https://c2sp.org/tlog-witness. It should be served at the /add-checkpoint
+// path of the witness URL.
+//
+// Checkpoints must be signed by one of the verifiers known for their origin.
+// The latest cosigned checkpoint for each log is kept in the provided store,
+// and is only replaced once a consistency proof from it has been verified.
+// Checkpoints are cosigned by each of the provided signers, which would
+// typically be created with note.NewSignerForCosignatureV1 from the
+// github.com/transparency-dev/formats/note package.
+func NewAddCheckpointHandler(logs KnownLogs, store log.CheckpointStore, signers ...note.Signer) http.Handler {
+	return &addCheckpointHandler{
+		logs:    logs,
+		store:   store,
+		signers: signers,
+	}
+}
+
+type addCheckpointHandler struct {
+	logs    KnownLogs
+	store   log.CheckpointStore
+	signers []note.Signer
+	// mu serialises the check and update of the store.
+	mu sync.Mutex
+}
+
+// httpError is an error which should be returned to the client with the given
+// status code.
+type httpError struct {
+	code int
+	msg  string
+}
+
+func (e *httpError) Error() string { return e.msg }
+
+// conflictError is returned when the old size in a request does not match the
+// size of the latest cosigned checkpoint, or when the checkpoint has the same
+// size as the latest cosigned checkpoint but a different root hash.
+type conflictError struct {
+	oldSize, latestSize uint64
+	rootMismatch        bool
+}
+
+func (e *conflictError) Error() string {
+	if e.rootMismatch {
+		return fmt.Sprintf("checkpoint root hash differs from that of the latest checkpoint of size %d", e.latestSize)
+	}
+	return fmt.Sprintf("old size %d does not match latest size %d", e.oldSize, e.latestSize)
+}
+
+func errorf(code int, format string, args ...any) error {
+	return &httpError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+func (h *addCheckpointHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAddCheckpointBody))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	cosigs, err := h.addCheckpoint(body)
+	if err != nil {
+		var hErr *httpError
+		var cErr *conflictError
+		switch {
+		case errors.As(err, &cErr):
+			w.Header().Set("Content-Type", contentTypeTLogSize)
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprintf(w, "%d\n", cErr.latestSize)
+		case errors.As(err, &hErr):
+			http.Error(w, hErr.msg, hErr.code)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(cosigs)
+}
+
+// addCheckpoint processes an add-checkpoint request body, returning the
+// cosignature lines for the checkpoint.
+func (h *addCheckpointHandler) addCheckpoint(body []byte) ([]byte, error) {
+	oldSize, proof, signed, err := parseAddCheckpointRequest(body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid request: %v", err)
+	}
+
+	origin, _, _ := strings.Cut(string(signed), "\n")
+	vs := h.logs.Verifiers(origin)
+	if len(vs) == 0 {
+		return nil, errorf(http.StatusNotFound, "unknown log %q", origin)
+	}
+	n, err := note.Open(signed, note.VerifierList(vs...))
+	if err != nil {
+		if uErr := (*note.UnverifiedNoteError)(nil); errors.As(err, &uErr) {
+			return nil, errorf(http.StatusForbidden, "no valid log signature on checkpoint")
+		}
+		return nil, errorf(http.StatusBadRequest, "invalid checkpoint note: %v", err)
+	}
+	cp := log.Checkpoint{}
+	if _, err := cp.UnmarshalStrict([]byte(n.Text)); err != nil {
+		return nil, errorf(http.StatusBadRequest, "%v", err)
+	}
+	if oldSize > cp.Size {
+		return nil, errorf(http.StatusBadRequest, "old size %d is larger than checkpoint size %d", oldSize, cp.Size)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := log.ID(cp.Origin)
+	latest, err := h.store.Latest(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read latest checkpoint: %v", err)
+	}
+	var latestSize uint64
+	var latestHash []byte
+	if latest != nil {
+		latestSize, latestHash = latest.Checkpoint.Size, latest.Checkpoint.Hash
+	}
+	if oldSize != latestSize {
+		return nil, &conflictError{oldSize: oldSize, latestSize: latestSize}
+	}
+	// A checkpoint of the latest size with another root hash conflicts with
+	// the checkpoint already cosigned, regardless of any proof.
+	if oldSize > 0 && oldSize == cp.Size && !bytes.Equal(cp.Hash, latestHash) {
+		return nil, &conflictError{oldSize: oldSize, latestSize: latestSize, rootMismatch: true}
+	}
+	if oldSize > 0 {
+		if err := log.VerifyConsistency(log.SHA256, oldSize, cp.Size, proof, latestHash, cp.Hash); err != nil {
+			return nil, errorf(http.StatusUnprocessableEntity, "invalid consistency proof: %v", err)
+		}
+	} else if len(proof) > 0 {
+		return nil, errorf(http.StatusUnprocessableEntity, "consistency proof from an empty tree must be empty")
+	}
+
+	cosigs, err := h.cosign([]byte(n.Text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to cosign checkpoint: %v", err)
+	}
+	if err := h.store.SetLatest(id, log.TrackedCheckpoint{Checkpoint: cp, Signed: signed}); err != nil {
+		return nil, fmt.Errorf("failed to store checkpoint: %v", err)
+	}
+	return cosigs, nil
+}
+
+// cosign returns the note signature lines from each of the signers over the
+// provided note text.
+func (h *addCheckpointHandler) cosign(text []byte) ([]byte, error) {
+	var b bytes.Buffer
+	for _, s := range h.signers {
+		sig, err := s.Sign(text)
+		if err != nil {
+			return nil, err
+		}
+		sig = append(binary.BigEndian.AppendUint32(nil, s.KeyHash()), sig...)
+		fmt.Fprintf(&b, "— %s %s\n", s.Name(), base64.StdEncoding.EncodeToString(sig))
+	}
+	return b.Bytes(), nil
+}
+
+// parseAddCheckpointRequest parses an add-checkpoint request body, which has
+// the form:
+//
+//	old <size>
+//	<zero or more base64 encoded consistency proof hashes, one per line>
+//	<blank line>
+//	<signed checkpoint note>
+func parseAddCheckpointRequest(body []byte) (uint64, [][]byte, []byte, error) {
+	line, rest, _ := bytes.Cut(body, []byte("\n"))
+	oldStr, ok := strings.CutPrefix(string(line), "old ")
+	if !ok {
+		return 0, nil, nil, errors.New("missing old size")
+	}
+	oldSize, err := strconv.ParseUint(oldStr, 10, 64)
+	if err != nil || strconv.FormatUint(oldSize, 10) != oldStr {
+		return 0, nil, nil, fmt.Errorf("invalid old size %q", oldStr)
+	}
+
+	var proof [][]byte
+	for {
+		if line, rest, ok = bytes.Cut(rest, []byte("\n")); !ok {
+			return 0, nil, nil, errors.New("missing checkpoint")
+		}
+		if len(line) == 0 {
+			break
+		}
+		if len(proof) == maxConsistencyProofHashes {
+			return 0, nil, nil, errors.New("consistency proof too long")
+		}
+		h, err := base64.StdEncoding.DecodeString(string(line))
+		if err != nil || len(h) != log.SHA256.Size() {
+			return 0, nil, nil, fmt.Errorf("invalid consistency proof hash %q", line)
+		}
+		proof = append(proof, h)
+	}
+	return oldSize, proof, rest, nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 The Tessera authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/formats/log"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/mod/sumdb/tlog"
+)
+
+const testLogOrigin = "example.com/log"
+
+// testTree is an in-memory tlog tree used to produce checkpoints and proofs.
+type testTree struct {
+	hashes []tlog.Hash
+	size   int64
+}
+
+func (tt *testTree) ReadHashes(indexes []int64) ([]tlog.Hash, error) {
+	r := make([]tlog.Hash, len(indexes))
+	for i, x := range indexes {
+		r[i] = tt.hashes[x]
+	}
+	return r, nil
+}
+
+func (tt *testTree) grow(t *testing.T, n int) {
+	t.Helper()
+	for range n {
+		hs, err := tlog.StoredHashes(tt.size, fmt.Appendf(nil, "leaf %d", tt.size), tt)
+		if err != nil {
+			t.Fatalf("StoredHashes: %v", err)
+		}
+		tt.hashes = append(tt.hashes, hs...)
+		tt.size++
+	}
+}
+
+func (tt *testTree) checkpoint(t *testing.T, size int64, skey string) []byte {
+	t.Helper()
+	h, err := tlog.TreeHash(size, tt)
+	if err != nil {
+		t.Fatalf("TreeHash: %v", err)
+	}
+	return signCheckpoint(t, log.Checkpoint{Origin: testLogOrigin, Size: uint64(size), Hash: h[:]}, skey)
+}
+
+func signCheckpoint(t *testing.T, cp log.Checkpoint, skey string) []byte {
+	t.Helper()
+	s, err := note.NewSigner(skey)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	n, err := note.Sign(&note.Note{Text: string(cp.Marshal())}, s)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return n
+}
+
+func (tt *testTree) proof(t *testing.T, from, to int64) []string {
+	t.Helper()
+	if from == 0 || from == to {
+		return nil
+	}
+	p, err := tlog.ProveTree(to, from, tt)
+	if err != nil {
+		t.Fatalf("ProveTree: %v", err)
+	}
+	r := make([]string, len(p))
+	for i, h := range p {
+		r[i] = base64.StdEncoding.EncodeToString(h[:])
+	}
+	return r
+}
+
+func addCheckpointBody(old int64, proof []string, cp []byte) string {
+	b := fmt.Sprintf("old %d\n", old)
+	for _, h := range proof {
+		b += h + "\n"
+	}
+	return b + "\n" + string(cp)
+}
+
+func TestAddCheckpointHandler(t *testing.T) {
+	logSK, logVK, err := note.GenerateKey(nil, testLogOrigin)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	otherSK, _, err := note.GenerateKey(nil, testLogOrigin)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	logV, err := note.NewVerifier(logVK)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	h := NewAddCheckpointHandler(LogVerifiers{testLogOrigin: {logV}}, log.NewMemoryCheckpointStore(), wit1Sign)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	tree := &testTree{}
+	tree.grow(t, 20)
+	forked := signCheckpoint(t, log.Checkpoint{Origin: testLogOrigin, Size: 10, Hash: make([]byte, 32)}, logSK)
+
+	for _, step := range []struct {
+		desc       string
+		method     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			desc:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		}, {
+			desc:       "malformed body",
+			body:       "new 0\n\n" + string(tree.checkpoint(t, 5, logSK)),
+			wantStatus: http.StatusBadRequest,
+		}, {
+			desc:       "non-canonical old size",
+			body:       "old 00\n\n" + string(tree.checkpoint(t, 5, logSK)),
+			wantStatus: http.StatusBadRequest,
+		}, {
+			desc:       "unknown log",
+			body:       addCheckpointBody(0, nil, []byte(strings.Replace(string(tree.checkpoint(t, 5, logSK)), testLogOrigin, "example.com/other", 1))),
+			wantStatus: http.StatusNotFound,
+		}, {
+			desc:       "not signed by log",
+			body:       addCheckpointBody(0, nil, tree.checkpoint(t, 5, otherSK)),
+			wantStatus: http.StatusForbidden,
+		}, {
+			desc:       "first checkpoint",
+			body:       addCheckpointBody(0, nil, tree.checkpoint(t, 5, logSK)),
+			wantStatus: http.StatusOK,
+		}, {
+			desc:       "stale old size",
+			body:       addCheckpointBody(0, nil, tree.checkpoint(t, 10, logSK)),
+			wantStatus: http.StatusConflict,
+			wantBody:   "5\n",
+		}, {
+			desc:       "old size larger than checkpoint",
+			body:       addCheckpointBody(5, nil, tree.checkpoint(t, 4, logSK)),
+			wantStatus: http.StatusBadRequest,
+		}, {
+			desc:       "bad consistency proof",
+			body:       addCheckpointBody(5, tree.proof(t, 6, 10), tree.checkpoint(t, 10, logSK)),
+			wantStatus: http.StatusUnprocessableEntity,
+		}, {
+			desc:       "consistent checkpoint",
+			body:       addCheckpointBody(5, tree.proof(t, 5, 10), tree.checkpoint(t, 10, logSK)),
+			wantStatus: http.StatusOK,
+		}, {
+			desc:       "same checkpoint again",
+			body:       addCheckpointBody(10, nil, tree.checkpoint(t, 10, logSK)),
+			wantStatus: http.StatusOK,
+		}, {
+			desc:       "same size different root",
+			body:       addCheckpointBody(10, nil, forked),
+			wantStatus: http.StatusConflict,
+			wantBody:   "10\n",
+		}, {
+			desc:       "proof with same size",
+			body:       addCheckpointBody(10, tree.proof(t, 5, 10), tree.checkpoint(t, 10, logSK)),
+			wantStatus: http.StatusUnprocessableEntity,
+		}, {
+			desc:       "consistent checkpoint after errors",
+			body:       addCheckpointBody(10, tree.proof(t, 10, 20), tree.checkpoint(t, 20, logSK)),
+			wantStatus: http.StatusOK,
+		},
+	} {
+		method := step.method
+		if method == "" {
+			method = http.MethodPost
+		}
+		req, err := http.NewRequest(method, srv.URL, strings.NewReader(step.body))
+		if err != nil {
+			t.Fatalf("NewRequest: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: Do: %v", step.desc, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: ReadAll: %v", step.desc, err)
+		}
+		if resp.StatusCode != step.wantStatus {
+			t.Fatalf("%s: got status %d, want %d (%s)", step.desc, resp.StatusCode, step.wantStatus, body)
+		}
+		if step.wantBody != "" && string(body) != step.wantBody {
+			t.Errorf("%s: got body %q, want %q", step.desc, body, step.wantBody)
+		}
+		if resp.StatusCode != http.StatusOK {
+			continue
+		}
+
+		// The response must contain a valid cosignature over the checkpoint.
+		_, _, signed, err := parseAddCheckpointRequest([]byte(step.body))
+		if err != nil {
+			t.Fatalf("%s: parseAddCheckpointRequest: %v", step.desc, err)
+		}
+		cosigned := append(signed, body...)
+		if !NewGroup(1, wit1).Satisfied(cosigned) {
+			t.Errorf("%s: response %q does not contain valid cosignature", step.desc, body)
+		}
+	}
+}
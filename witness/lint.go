@@ -0,0 +1,300 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"fmt"
+	"io/fs"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// LintKind identifies the kind of problem reported by a LintWarning.
+type LintKind string
+
+const (
+	// LintUnreachable is reported for a witness or group which is defined
+	// but cannot contribute to satisfying the quorum.
+	LintUnreachable LintKind = "unreachable"
+	// LintDuplicateKey is reported for a witness whose key is also used by
+	// an earlier witness.
+	LintDuplicateKey LintKind = "duplicate-key"
+	// LintDuplicateURL is reported for a witness whose URL is also used by
+	// an earlier witness.
+	LintDuplicateURL LintKind = "duplicate-url"
+	// LintZeroThreshold is reported for a group which is satisfied without
+	// any cosignatures.
+	LintZeroThreshold LintKind = "zero-threshold"
+	// LintSinglePointOfFailure is reported for a witness without whose
+	// cosignature the quorum cannot be satisfied.
+	LintSinglePointOfFailure LintKind = "single-point-of-failure"
+)
+
+// LintWarning describes a policy which is valid, but likely not as intended.
+type LintWarning struct {
+	// File is the name of the policy file containing the definition at
+	// fault, if the policy was read from a file system.
+	File string
+	// Line is the 1-based line number of the definition at fault.
+	Line int
+	// Name is the name of the component at fault.
+	Name    string
+	Kind    LintKind
+	Message string
+}
+
+func (w LintWarning) String() string {
+	if w.File != "" {
+		return fmt.Sprintf("%s:%d: %s: %s", w.File, w.Line, w.Kind, w.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", w.Line, w.Kind, w.Message)
+}
+
+// PolicyReport is the result of linting a policy.
+type PolicyReport struct {
+	// Warnings holds the problems found, ordered by file and line.
+	Warnings []LintWarning
+	// Witnesses is the number of witnesses which can contribute to
+	// satisfying the quorum.
+	Witnesses int
+	// MinSatisfying is the smallest number of witnesses whose cosignatures
+	// satisfy the quorum. This is both the number of witnesses which must
+	// collude to have a checkpoint accepted, and the number which must be
+	// available for the quorum to be satisfiable at all.
+	MinSatisfying int
+	// MaxUnavailable is the largest number of witnesses which may be
+	// unavailable, whichever they are, without preventing the quorum from
+	// being satisfied.
+	MaxUnavailable int
+}
+
+// maxExactWitnesses bounds the number of witnesses for which the metrics of a
+// policy which shares witnesses between groups are computed exhaustively.
+const maxExactWitnesses = 20
+
+// LintPolicy parses the policy, as described by ParsePolicy, and reports any
+// problems which do not prevent it from being used, along with metrics which
+// describe how resilient it is to colluding or unavailable witnesses.
+//
+// An error is returned only if the policy cannot be parsed.
+//
+// The metrics are exact for policies in which each witness is reachable from
+// the quorum in only one way, and for those with at most 20 witnesses. Larger
+// policies which share witnesses between groups count each occurrence of a
+// witness separately, and so may overstate their resilience.
+func LintPolicy(p []byte) (*PolicyReport, error) {
+	pol, err := parsePolicy(p)
+	if err != nil {
+		return nil, err
+	}
+	return lint(pol, ""), nil
+}
+
+// LintPolicyFS parses the named policy file from fsys, as described by
+// ParsePolicyFS, and lints it as LintPolicy does.
+//
+// Included files are expected to be shared between policies, so components
+// defined in them which are not used by the quorum are not reported.
+func LintPolicyFS(fsys fs.FS, name string) (*PolicyReport, error) {
+	pol, err := parsePolicyFS(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return lint(pol, name), nil
+}
+
+// lint analyses the policy parsed from the named root file.
+func lint(pol *policy, root string) *PolicyReport {
+	r := &PolicyReport{}
+	reachable, paths := pol.reachable()
+
+	keys := make(map[string]string)
+	urls := make(map[string]string)
+	for _, name := range pol.names {
+		if w, ok := pol.witnesses[name]; ok {
+			if !reachable[name] && w.file == root {
+				r.warn(w.file, w.line, name, LintUnreachable, "witness %q is not used by the quorum", name)
+			}
+			if other, ok := keys[keyMaterial(w.vkey)]; ok {
+				r.warn(w.file, w.line, name, LintDuplicateKey, "witness %q has the same key as witness %q", name, other)
+			} else {
+				keys[keyMaterial(w.vkey)] = name
+			}
+			if other, ok := urls[w.w.URL]; ok {
+				r.warn(w.file, w.line, name, LintDuplicateURL, "witness %q has the same URL as witness %q", name, other)
+			} else {
+				urls[w.w.URL] = name
+			}
+			continue
+		}
+		g := pol.groups[name]
+		if !reachable[name] && g.file == root {
+			r.warn(g.file, g.line, name, LintUnreachable, "group %q is not used by the quorum", name)
+		}
+		if g.n == 0 {
+			r.warn(g.file, g.line, name, LintZeroThreshold, "group %q is satisfied without any cosignatures", name)
+		}
+	}
+
+	var witnesses []string
+	for _, name := range pol.names {
+		if _, ok := pol.witnesses[name]; ok && reachable[name] {
+			witnesses = append(witnesses, name)
+		}
+	}
+	r.Witnesses = len(witnesses)
+	quorum := pol.quorumGroup()
+	for _, name := range witnesses {
+		id := pol.witnesses[name].w.id()
+		if !quorum.satisfiableWith(func(w Witness) bool { return w.id() != id }) {
+			r.warn(pol.witnesses[name].file, pol.witnesses[name].line, name, LintSinglePointOfFailure, "the quorum cannot be satisfied without witness %q", name)
+		}
+	}
+	slices.SortStableFunc(r.Warnings, func(a, b LintWarning) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+
+	shared := false
+	for _, n := range paths {
+		shared = shared || n > 1
+	}
+	if shared && len(witnesses) <= maxExactWitnesses {
+		r.MinSatisfying, r.MaxUnavailable = pol.exactMetrics(witnesses)
+	} else {
+		minSat, minBlock := pol.treeMetrics(pol.quorum)
+		r.MinSatisfying, r.MaxUnavailable = minSat, min(minBlock-1, r.Witnesses)
+	}
+	return r
+}
+
+func (r *PolicyReport) warn(file string, line int, name string, kind LintKind, format string, args ...any) {
+	r.Warnings = append(r.Warnings, LintWarning{File: file, Line: line, Name: name, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// keyMaterial returns the encoded key from a vkey, which identifies the key
+// regardless of the name it is given.
+func keyMaterial(vkey string) string {
+	if parts := strings.SplitN(vkey, "+", 3); len(parts) == 3 {
+		return parts[2]
+	}
+	return vkey
+}
+
+// reachable returns the set of components reachable from the quorum, along
+// with the number of distinct paths by which each is reached.
+func (p *policy) reachable() (map[string]bool, map[string]int) {
+	reachable := make(map[string]bool)
+	paths := make(map[string]int)
+	var visit func(name string)
+	visit = func(name string) {
+		reachable[name] = true
+		paths[name]++
+		for _, c := range p.groups[name].children {
+			visit(c)
+		}
+	}
+	if p.quorum != "none" {
+		visit(p.quorum)
+	}
+	return reachable, paths
+}
+
+// exactMetrics computes MinSatisfying and MaxUnavailable for the quorum by
+// considering every subset of the given witnesses.
+func (p *policy) exactMetrics(witnesses []string) (minSat, maxUnavailable int) {
+	// Witnesses are identified by their key and URL, so names for the same
+	// witness share its bits.
+	bit := make(map[string]uint64, len(witnesses))
+	for i, name := range witnesses {
+		bit[p.witnesses[name].w.id()] |= 1 << i
+	}
+	quorum := p.quorumGroup()
+	satisfied := func(mask uint64) bool {
+		return quorum.satisfiableWith(func(w Witness) bool { return mask&bit[w.id()] != 0 })
+	}
+	all := uint64(1)<<len(witnesses) - 1
+	minSat, minBlock := len(witnesses), len(witnesses)+1
+	for mask := uint64(0); mask <= all; mask++ {
+		n := bits.OnesCount64(mask)
+		if n < minSat && satisfied(mask) {
+			minSat = n
+		}
+		if n < minBlock && !satisfied(all&^mask) {
+			minBlock = n
+		}
+	}
+	return minSat, minBlock - 1
+}
+
+// treeMetrics returns the smallest number of witnesses which satisfy the
+// named component, and the smallest number whose absence prevents it from
+// being satisfied, treating each occurrence of a witness as distinct. The
+// latter is larger than the number of witnesses if the component cannot be
+// prevented from being satisfied.
+func (p *policy) treeMetrics(name string) (minSat, minBlock int) {
+	const unblockable = 1 << 30
+	if name == "none" {
+		return 0, unblockable
+	}
+	g, ok := p.groups[name]
+	if !ok {
+		return 1, 1
+	}
+	if g.n == 0 {
+		return 0, unblockable
+	}
+	sats := make([]int, len(g.children))
+	blocks := make([]int, len(g.children))
+	weights := make([]int, len(g.children))
+	for i, c := range g.children {
+		sats[i], blocks[i] = p.treeMetrics(c)
+		weights[i] = g.weight(i)
+	}
+	// The group is satisfied by satisfying children whose weights reach the
+	// threshold, and blocked by blocking enough children that the weight of
+	// those remaining falls below it.
+	total := totalWeight(len(g.children), g.weights)
+	return minCost(sats, weights, g.n, unblockable), minCost(blocks, weights, total-g.n+1, unblockable)
+}
+
+// minCost returns the smallest total cost of a selection of items whose
+// weights add up to at least target, with costs saturating at limit.
+func minCost(costs, weights []int, target, limit int) int {
+	// best[w] is the smallest cost of reaching a total weight of w, or at
+	// least w for the target itself.
+	best := make([]int, target+1)
+	for w := 1; w <= target; w++ {
+		best[w] = limit
+	}
+	for i, c := range costs {
+		for w := target; w > 0; w-- {
+			from := max(w-weights[i], 0)
+			best[w] = min(best[w], min(best[from]+c, limit))
+		}
+	}
+	return best[target]
+}
+
+// weight returns the weight of the i-th child of the group.
+func (g groupDef) weight(i int) int {
+	if g.weights == nil {
+		return 1
+	}
+	return g.weights[i]
+}
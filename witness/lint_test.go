@@ -0,0 +1,246 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const (
+	wit4_vkey = "sigsum.org+e4ade967+AZuUY6B08pW3QVHu8uvsrxWPcAv9nykap2Nb4oxCee+r"
+	wit5_vkey = "example.com+3753d3de+AebBhMcghIUoavZpjuDofa4sW6fYHyVn7gvwDBfvkvuM"
+)
+
+func TestLintPolicy(t *testing.T) {
+	for _, test := range []struct {
+		desc   string
+		policy string
+		want   PolicyReport
+	}{
+		{
+			desc: "clean",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+group g1 2 w1 w2 w3
+quorum g1
+`,
+			want: PolicyReport{Witnesses: 3, MinSatisfying: 2, MaxUnavailable: 1},
+		}, {
+			desc: "single witness quorum",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+quorum w1
+`,
+			want: PolicyReport{
+				Warnings: []LintWarning{
+					{Line: 2, Name: "w1", Kind: LintSinglePointOfFailure},
+				},
+				Witnesses:     1,
+				MinSatisfying: 1,
+			},
+		}, {
+			desc: "problems",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w2.example.com/
+witness w4 ` + wit1_vkey + ` https://w4.example.com/
+witness w5 ` + wit4_vkey + ` https://w5.example.com/
+group g0 0 w5
+group g1 any w2 w3
+group g2 any w4
+group q all w1 g1 g0
+quorum q
+`,
+			want: PolicyReport{
+				Warnings: []LintWarning{
+					{Line: 2, Name: "w1", Kind: LintSinglePointOfFailure},
+					{Line: 4, Name: "w3", Kind: LintDuplicateURL},
+					{Line: 5, Name: "w4", Kind: LintUnreachable},
+					{Line: 5, Name: "w4", Kind: LintDuplicateKey},
+					{Line: 7, Name: "g0", Kind: LintZeroThreshold},
+					{Line: 9, Name: "g2", Kind: LintUnreachable},
+				},
+				Witnesses:     4,
+				MinSatisfying: 2,
+			},
+		}, {
+			desc: "shared witness",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+group g1 any w1 w2
+group g2 any w1 w3
+group q all g1 g2
+quorum q
+`,
+			// w1 alone satisfies both groups, but both w1 and one of the
+			// others must be unavailable to prevent the quorum.
+			want: PolicyReport{Witnesses: 3, MinSatisfying: 1, MaxUnavailable: 1},
+		}, {
+			desc: "nested",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+witness w4 ` + wit4_vkey + ` https://w4.example.com/
+witness w5 ` + wit5_vkey + ` https://w5.example.com/
+group g1 all w1 w2
+group g2 any w3 w4 w5
+group q any g1 g2
+quorum q
+`,
+			want: PolicyReport{Witnesses: 5, MinSatisfying: 1, MaxUnavailable: 3},
+		}, {
+			desc: "weighted",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+witness w4 ` + wit4_vkey + ` https://w4.example.com/
+group g1 weight>=3 w1:2 w2 w3 w4
+quorum g1
+`,
+			// w1 and any other satisfy the quorum, as do all but w1, so any
+			// one witness may be unavailable but not w1 and another.
+			want: PolicyReport{Witnesses: 4, MinSatisfying: 2, MaxUnavailable: 1},
+		}, {
+			desc: "weighted single point of failure",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+group g1 weight>=4 w1:3 w2 w3
+quorum g1
+`,
+			want: PolicyReport{
+				Warnings: []LintWarning{
+					{Line: 2, Name: "w1", Kind: LintSinglePointOfFailure},
+				},
+				Witnesses:     3,
+				MinSatisfying: 2,
+			},
+		}, {
+			desc: "quorum none",
+			policy: `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+quorum none
+`,
+			want: PolicyReport{
+				Warnings: []LintWarning{
+					{Line: 2, Name: "w1", Kind: LintUnreachable},
+				},
+			},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := LintPolicy([]byte(test.policy))
+			if err != nil {
+				t.Fatalf("LintPolicy: %v", err)
+			}
+			for _, w := range got.Warnings {
+				if !strings.Contains(w.Message, fmt.Sprintf("%q", w.Name)) {
+					t.Errorf("warning %v does not name %q", w, w.Name)
+				}
+			}
+			if diff := cmp.Diff(test.want, *got, cmpopts.IgnoreFields(LintWarning{}, "Message")); diff != "" {
+				t.Errorf("LintPolicy diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLintPolicy_Metrics(t *testing.T) {
+	// Compare the exhaustive computation with the one which assumes no
+	// witnesses are shared, on policies where both are exact.
+	policies := []string{
+		"group g 2 w1 w2 w3\nquorum g\n",
+		"group g1 all w1 w2\ngroup g2 any w3 w4 w5\ngroup q any g1 g2\nquorum q\n",
+		"group g1 2 w1 w2 w3\ngroup g2 all w4 w5\ngroup q all g1 g2\nquorum q\n",
+		"group g1 any w1 w2\ngroup g2 0 w3\ngroup q 2 g1 g2 w4 w5\nquorum q\n",
+		"group g1 weight>=3 w1:2 w2 w3\ngroup q weight>=4 g1:3 w4:2 w5\nquorum q\n",
+		"group g1 weight>=5 w1:3 w2:2 w3:2 w4 w5\nquorum g1\n",
+	}
+	witnesses := "witness w1 " + wit1_vkey + " https://w1.example.com/\n" +
+		"witness w2 " + wit2_vkey + " https://w2.example.com/\n" +
+		"witness w3 " + wit3_vkey + " https://w3.example.com/\n" +
+		"witness w4 " + wit4_vkey + " https://w4.example.com/\n" +
+		"witness w5 " + wit5_vkey + " https://w5.example.com/\n"
+	for _, p := range policies {
+		pol, err := parsePolicy([]byte(witnesses + p))
+		if err != nil {
+			t.Fatalf("parsePolicy(%q): %v", p, err)
+		}
+		names := []string{"w1", "w2", "w3", "w4", "w5"}
+		gotSat, gotUnavailable := pol.exactMetrics(names)
+		wantSat, minBlock := pol.treeMetrics(pol.quorum)
+		if wantUnavailable := min(minBlock-1, len(names)); gotSat != wantSat || gotUnavailable != wantUnavailable {
+			t.Errorf("%q: exactMetrics() = %d, %d, treeMetrics() = %d, %d", p, gotSat, gotUnavailable, wantSat, wantUnavailable)
+		}
+	}
+}
+
+func TestLintPolicyFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"common": {Data: []byte(`witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+`)},
+		"policy": {Data: []byte(`include common
+witness w4 ` + wit4_vkey + ` https://w4.example.com/
+witness w5 ` + wit1_vkey + ` https://w5.example.com/
+group g1 any w1 w2
+quorum g1
+`)},
+	}
+	got, err := LintPolicyFS(fsys, "policy")
+	if err != nil {
+		t.Fatalf("LintPolicyFS: %v", err)
+	}
+	// w3 is unused, but is in a shared file so isn't reported.
+	want := []string{
+		`policy:2: unreachable: witness "w4" is not used by the quorum`,
+		`policy:3: unreachable: witness "w5" is not used by the quorum`,
+		`policy:3: duplicate-key: witness "w5" has the same key as witness "w1"`,
+	}
+	var gotWarnings []string
+	for _, w := range got.Warnings {
+		gotWarnings = append(gotWarnings, w.String())
+	}
+	if diff := cmp.Diff(want, gotWarnings); diff != "" {
+		t.Errorf("LintPolicyFS warnings diff (-want +got):\n%s", diff)
+	}
+	if got.Witnesses != 2 || got.MinSatisfying != 1 || got.MaxUnavailable != 1 {
+		t.Errorf("got metrics %d, %d, %d, want 2, 1, 1", got.Witnesses, got.MinSatisfying, got.MaxUnavailable)
+	}
+}
+
+func TestLintPolicy_ParseError(t *testing.T) {
+	_, err := LintPolicy([]byte("witness w1 " + wit1_vkey + " https://w1.example.com/\ngroup g1 any w2\nquorum g1\n"))
+	var pErr *ParseError
+	if !errors.As(err, &pErr) || pErr.Line != 2 || !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("LintPolicy() = %v, want ParseError for line 2 wrapping %v", err, ErrUnknownComponent)
+	}
+}
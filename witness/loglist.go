@@ -0,0 +1,317 @@
+// Copyright 2026 The Tessera authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/transparency-dev/formats/log"
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// witnessNetworkHeader is the first line of a witness network log list.
+const witnessNetworkHeader = "logs/v0"
+
+// LogInfo describes a log which a witness or verifier follows.
+type LogInfo struct {
+	// Origin is the origin line of the log's checkpoints.
+	Origin string
+	// VKey is the verifier key for the log's checkpoint signatures.
+	VKey string
+	// Verifier is constructed from VKey.
+	Verifier note.Verifier
+	// URL is the optional root URL of the log.
+	URL string
+	// Quorum optionally names the component of a witness policy which must
+	// cosign the log's checkpoints, for lists used by verifiers which apply
+	// different policies to different logs.
+	Quorum string
+	// QPD is the expected number of checkpoint updates per day, if known.
+	QPD float64
+	// Contact is optional contact information for the log operator.
+	Contact string
+}
+
+// LogList is a set of logs, keyed by the log.ID of their origin.
+//
+// LogList implements KnownLogs, and so can be used to configure the logs
+// accepted by NewAddCheckpointHandler.
+type LogList map[string]LogInfo
+
+// Lookup returns the details of the log with the given origin, if present.
+func (l LogList) Lookup(origin string) (LogInfo, bool) {
+	i, ok := l[log.ID(origin)]
+	return i, ok
+}
+
+// Verifiers implements KnownLogs.
+func (l LogList) Verifiers(origin string) []note.Verifier {
+	i, ok := l.Lookup(origin)
+	if !ok {
+		return nil
+	}
+	return []note.Verifier{i.Verifier}
+}
+
+// Indexes of the fields of a log line, which are used to locate the problems
+// found by LogList.add.
+const (
+	logFieldOrigin = 1
+	logFieldVKey   = 2
+	logFieldURL    = 3
+)
+
+// add validates and adds the provided log to the list. The field of a log
+// line to which a problem relates is recorded with atField.
+func (l LogList) add(i LogInfo, requireKeyName bool) error {
+	if i.Origin == "" {
+		return atField(logFieldOrigin, fmt.Errorf("log with vkey %q has empty origin", i.VKey))
+	}
+	v, err := f_note.NewVerifier(i.VKey)
+	if err != nil {
+		return atField(logFieldVKey, fmt.Errorf("invalid vkey for log %q: %w", i.Origin, err))
+	}
+	if requireKeyName && v.Name() != i.Origin {
+		return atField(logFieldVKey, fmt.Errorf("vkey name %q does not match log origin %q", v.Name(), i.Origin))
+	}
+	if i.URL != "" {
+		if _, err := url.Parse(i.URL); err != nil {
+			return atField(logFieldURL, fmt.Errorf("invalid URL %q for log %q: %w", i.URL, i.Origin, err))
+		}
+	}
+	id := log.ID(i.Origin)
+	if _, ok := l[id]; ok {
+		return atField(logFieldOrigin, fmt.Errorf("duplicate log origin %q", i.Origin))
+	}
+	i.Verifier = v
+	l[id] = i
+	return nil
+}
+
+// ParseLogList parses a list of logs, one per line, in the form:
+//
+//	log <origin> <vkey> [url] [quorum=<name>]
+//
+// where the quorum names the component of a witness policy which must cosign
+// the log's checkpoints. Blank lines and comments starting with # are ignored.
+//
+// Parsing continues past problems with individual lines, so that every
+// problem is reported. These are returned as ParseErrors, which holds a
+// *ParseError with the line and column of each.
+//
+// If requireKeyName is true, the name of each vkey must be the same as the
+// log's origin, as is recommended by https://c2sp.org/tlog-checkpoint. Some
+// ecosystems, such as the Go checksum database, use a different key name,
+// and so this requirement is optional.
+func ParseLogList(b []byte, requireKeyName bool) (LogList, error) {
+	scanner := bufio.NewScanner(bytes.NewBuffer(b))
+	l := make(LogList)
+	var errs ParseErrors
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields, cols := splitFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := l.parseLine(fields, requireKeyName); err != nil {
+			errs = append(errs, &ParseError{Line: lineNum, Column: cols[min(fieldIndex(err), len(cols)-1)], Err: err})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return l, nil
+}
+
+// parseLine parses the fields of a line of a log list into the list.
+func (l LogList) parseLine(fields []string, requireKeyName bool) error {
+	if fields[0] != "log" {
+		return fmt.Errorf("unknown keyword: %q", fields[0])
+	}
+	if len(fields) < 3 {
+		return atField(len(fields)-1, errors.New("log definition must have an origin and vkey"))
+	}
+	i := LogInfo{Origin: fields[1], VKey: fields[2]}
+	for j, f := range fields[3:] {
+		if q, ok := strings.CutPrefix(f, "quorum="); ok {
+			if i.Quorum != "" || q == "" {
+				return atField(3+j, fmt.Errorf("invalid quorum %q", f))
+			}
+			i.Quorum = q
+			continue
+		}
+		if i.URL != "" || i.Quorum != "" {
+			return atField(3+j, fmt.Errorf("unexpected field %q", f))
+		}
+		i.URL = f
+	}
+	return l.add(i, requireKeyName)
+}
+
+// sorted returns the logs in the list, ordered by origin.
+func (l LogList) sorted() []LogInfo {
+	infos := make([]LogInfo, 0, len(l))
+	for _, i := range l {
+		infos = append(infos, i)
+	}
+	slices.SortFunc(infos, func(a, b LogInfo) int { return strings.Compare(a.Origin, b.Origin) })
+	return infos
+}
+
+// Marshal returns the list in the format accepted by ParseLogList, ordered by
+// origin. The QPD and Contact fields are not represented in this format, but
+// are by MarshalWitnessNetwork.
+func (l LogList) Marshal() []byte {
+	var b bytes.Buffer
+	for _, i := range l.sorted() {
+		fmt.Fprintf(&b, "log %s %s", i.Origin, i.VKey)
+		if i.URL != "" {
+			fmt.Fprintf(&b, " %s", i.URL)
+		}
+		if i.Quorum != "" {
+			fmt.Fprintf(&b, " quorum=%s", i.Quorum)
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// MarshalWitnessNetwork returns the list in the format accepted by
+// ParseWitnessNetworkLogList, ordered by origin. The URL and Quorum fields
+// are not represented in this format, but are by Marshal.
+func (l LogList) MarshalWitnessNetwork() []byte {
+	var b bytes.Buffer
+	b.WriteString(witnessNetworkHeader + "\n")
+	for _, i := range l.sorted() {
+		fmt.Fprintf(&b, "\nvkey %s\n", i.VKey)
+		if name, _, _ := strings.Cut(i.VKey, "+"); name != i.Origin {
+			fmt.Fprintf(&b, "origin %s\n", i.Origin)
+		}
+		if i.QPD != 0 {
+			fmt.Fprintf(&b, "qpd %s\n", strconv.FormatFloat(i.QPD, 'g', -1, 64))
+		}
+		if i.Contact != "" {
+			fmt.Fprintf(&b, "contact %s\n", i.Contact)
+		}
+	}
+	return b.Bytes()
+}
+
+// ParseWitnessNetworkLogList parses a log list in the format published by the
+// public witness network, described at
+// https://github.com/transparency-dev/witness-network/blob/main/log-list-format.md.
+//
+// The list starts with the line "logs/v0", and each log entry starts with a
+// "vkey" line, optionally followed by "origin", "qpd" and "contact" lines
+// applying to that log. Where no origin is given, it defaults to the name of
+// the vkey.
+//
+// As with ParseLogList, every problem found is reported in ParseErrors.
+// Problems with a log as a whole, such as a duplicate origin, are reported
+// against its vkey line.
+func ParseWitnessNetworkLogList(b []byte) (LogList, error) {
+	scanner := bufio.NewScanner(bytes.NewBuffer(b))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != witnessNetworkHeader {
+		return nil, ParseErrors{{Line: 1, Err: fmt.Errorf("log list must start with %q", witnessNetworkHeader)}}
+	}
+
+	l := make(LogList)
+	var errs ParseErrors
+	var cur *LogInfo
+	lineNum, curLine := 1, 0
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		if cur.Origin == "" {
+			v, err := f_note.NewVerifier(cur.VKey)
+			if err != nil {
+				errs = append(errs, &ParseError{Line: curLine, Err: fmt.Errorf("invalid vkey %q: %w", cur.VKey, err)})
+				cur = nil
+				return
+			}
+			cur.Origin = v.Name()
+		}
+		if err := l.add(*cur, false); err != nil {
+			errs = append(errs, &ParseError{Line: curLine, Err: err})
+		}
+		cur = nil
+	}
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		fields, cols := splitFields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		keyword := fields[0]
+		value := strings.TrimSpace(line[cols[0]-1+len(keyword):])
+		valueCol := cols[min(1, len(cols)-1)]
+		parseErr := func(col int, format string, args ...any) {
+			errs = append(errs, &ParseError{Line: lineNum, Column: col, Err: fmt.Errorf(format, args...)})
+		}
+		if keyword != "vkey" && cur == nil {
+			parseErr(cols[0], "%q line before first vkey line", keyword)
+			continue
+		}
+		switch keyword {
+		case "vkey":
+			flush()
+			cur, curLine = &LogInfo{VKey: value}, lineNum
+		case "origin":
+			if cur.Origin != "" {
+				parseErr(valueCol, "duplicate origin for log with vkey %q", cur.VKey)
+				continue
+			}
+			cur.Origin = value
+		case "qpd":
+			qpd, err := strconv.ParseFloat(value, 64)
+			if err != nil || qpd < 0 {
+				parseErr(valueCol, "invalid qpd %q for log with vkey %q", value, cur.VKey)
+				continue
+			}
+			cur.QPD = qpd
+		case "contact":
+			cur.Contact = value
+		default:
+			parseErr(cols[0], "unknown keyword: %q", keyword)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return l, nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 The Tessera authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/transparency-dev/formats/log"
+)
+
+const (
+	sigsumVKey  = "sigsum.org+e4ade967+AZuUY6B08pW3QVHu8uvsrxWPcAv9nykap2Nb4oxCee+r"
+	exampleVKey = "example.com+3753d3de+AebBhMcghIUoavZpjuDofa4sW6fYHyVn7gvwDBfvkvuM"
+)
+
+func TestParseLogList(t *testing.T) {
+	list := `
+# comment
+log sigsum.org ` + sigsumVKey + ` https://sigsum.org/log/ quorum=sigsum-witnesses
+  log   example.com   ` + exampleVKey + `   # no URL
+`
+	l, err := ParseLogList([]byte(list), true)
+	if err != nil {
+		t.Fatalf("ParseLogList: %v", err)
+	}
+	if len(l) != 2 {
+		t.Fatalf("got %d logs, want 2", len(l))
+	}
+	i, ok := l[log.ID("sigsum.org")]
+	if !ok {
+		t.Fatal("sigsum.org not found by log ID")
+	}
+	if i.URL != "https://sigsum.org/log/" || i.Quorum != "sigsum-witnesses" || i.VKey != sigsumVKey || i.Verifier == nil {
+		t.Errorf("unexpected log info: %+v", i)
+	}
+	if vs := l.Verifiers("example.com"); len(vs) != 1 || vs[0].Name() != "example.com" {
+		t.Errorf("Verifiers(example.com) = %v", vs)
+	}
+	if vs := l.Verifiers("unknown"); vs != nil {
+		t.Errorf("Verifiers(unknown) = %v, want nil", vs)
+	}
+
+	// Marshalling must round trip.
+	l2, err := ParseLogList(l.Marshal(), true)
+	if err != nil {
+		t.Fatalf("ParseLogList(Marshal()): %v", err)
+	}
+	if got, want := string(l2.Marshal()), string(l.Marshal()); got != want {
+		t.Errorf("round trip got %q, want %q", got, want)
+	}
+}
+
+func TestParseLogList_KeyName(t *testing.T) {
+	list := "log go.sum " + sigsumVKey + "\n"
+	if _, err := ParseLogList([]byte(list), true); err == nil {
+		t.Error("ParseLogList with mismatched key name succeeded, want error")
+	}
+	l, err := ParseLogList([]byte(list), false)
+	if err != nil {
+		t.Fatalf("ParseLogList: %v", err)
+	}
+	if _, ok := l.Lookup("go.sum"); !ok {
+		t.Error("go.sum not found")
+	}
+}
+
+func TestParseLogList_Errors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		list string
+	}{
+		{name: "unknown keyword", list: "witness sigsum.org " + sigsumVKey},
+		{name: "missing vkey", list: "log sigsum.org"},
+		{name: "too many fields", list: "log sigsum.org " + sigsumVKey + " https://sigsum.org/ extra"},
+		{name: "bad vkey", list: "log sigsum.org sigsum.org+e4ade967+AAAA"},
+		{name: "bad URL", list: "log sigsum.org " + sigsumVKey + " ://"},
+		{name: "duplicate origin", list: "log sigsum.org " + sigsumVKey + "\nlog sigsum.org " + sigsumVKey},
+		{name: "empty quorum", list: "log sigsum.org " + sigsumVKey + " quorum="},
+		{name: "duplicate quorum", list: "log sigsum.org " + sigsumVKey + " quorum=a quorum=b"},
+		{name: "URL after quorum", list: "log sigsum.org " + sigsumVKey + " quorum=a https://sigsum.org/"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseLogList([]byte(test.list), true); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestParseLogList_ParseErrors(t *testing.T) {
+	list := "log sigsum.org " + sigsumVKey + "\n" +
+		"witness w1\n" +
+		"log go.sum " + sigsumVKey + "\n" +
+		"  log sigsum.org " + sigsumVKey + " ://\n" +
+		"log sigsum.org " + sigsumVKey + "\n"
+	_, err := ParseLogList([]byte(list), true)
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ParseLogList() = %v, want ParseErrors", err)
+	}
+	want := []string{
+		`line 2, column 1: unknown keyword: "witness"`,
+		`line 3, column 12: vkey name "sigsum.org" does not match log origin "go.sum"`,
+		`line 4, column 83: invalid URL "://"`,
+		`line 5, column 5: duplicate log origin "sigsum.org"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(errs), len(want), err)
+	}
+	for i, want := range want {
+		if got := errs[i].Error(); !strings.HasPrefix(got, want) {
+			t.Errorf("error %d = %q, want prefix %q", i, got, want)
+		}
+	}
+}
+
+func TestParseWitnessNetworkLogList(t *testing.T) {
+	list := `logs/v0
+
+# A log whose origin is its key name.
+vkey ` + sigsumVKey + `
+qpd 1440
+contact ops@sigsum.org
+
+vkey ` + exampleVKey + `
+origin example.com/log/v1
+qpd 24
+`
+	l, err := ParseWitnessNetworkLogList([]byte(list))
+	if err != nil {
+		t.Fatalf("ParseWitnessNetworkLogList: %v", err)
+	}
+	i, ok := l.Lookup("sigsum.org")
+	if !ok {
+		t.Fatal("sigsum.org not found")
+	}
+	if i.QPD != 1440 || i.Contact != "ops@sigsum.org" {
+		t.Errorf("unexpected log info: %+v", i)
+	}
+	i, ok = l.Lookup("example.com/log/v1")
+	if !ok {
+		t.Fatal("example.com/log/v1 not found")
+	}
+	if i.QPD != 24 || i.VKey != exampleVKey {
+		t.Errorf("unexpected log info: %+v", i)
+	}
+
+	// Marshalling must round trip.
+	l2, err := ParseWitnessNetworkLogList(l.MarshalWitnessNetwork())
+	if err != nil {
+		t.Fatalf("ParseWitnessNetworkLogList(MarshalWitnessNetwork()): %v", err)
+	}
+	for id, i := range l {
+		i2 := l2[id]
+		if i2.Origin != i.Origin || i2.VKey != i.VKey || i2.QPD != i.QPD || i2.Contact != i.Contact {
+			t.Errorf("round trip got %+v, want %+v", i2, i)
+		}
+	}
+	if len(l2) != len(l) {
+		t.Errorf("round trip got %d logs, want %d", len(l2), len(l))
+	}
+	if got, want := string(l2.MarshalWitnessNetwork()), string(l.MarshalWitnessNetwork()); got != want {
+		t.Errorf("round trip got %q, want %q", got, want)
+	}
+}
+
+func TestParseWitnessNetworkLogList_Errors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		list string
+	}{
+		{name: "missing header", list: "vkey " + sigsumVKey},
+		{name: "origin before vkey", list: "logs/v0\norigin sigsum.org\nvkey " + sigsumVKey},
+		{name: "duplicate origin line", list: "logs/v0\nvkey " + sigsumVKey + "\norigin a\norigin b"},
+		{name: "bad qpd", list: "logs/v0\nvkey " + sigsumVKey + "\nqpd lots"},
+		{name: "bad vkey", list: "logs/v0\nvkey sigsum.org+e4ade967+AAAA"},
+		{name: "unknown keyword", list: "logs/v0\nvkey " + sigsumVKey + "\nurl https://sigsum.org/"},
+		{name: "duplicate log", list: "logs/v0\nvkey " + sigsumVKey + "\nvkey " + sigsumVKey},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseWitnessNetworkLogList([]byte(test.list)); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestParseWitnessNetworkLogList_ParseErrors(t *testing.T) {
+	list := "logs/v0\n" +
+		"contact ops@example.com\n" +
+		"vkey " + sigsumVKey + "\n" +
+		"qpd  lots\n" +
+		"url https://sigsum.org/\n" +
+		"vkey " + sigsumVKey + "\n"
+	_, err := ParseWitnessNetworkLogList([]byte(list))
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ParseWitnessNetworkLogList() = %v, want ParseErrors", err)
+	}
+	want := []string{
+		`line 2, column 1: "contact" line before first vkey line`,
+		`line 4, column 6: invalid qpd "lots"`,
+		`line 5, column 1: unknown keyword: "url"`,
+		`line 6: duplicate log origin "sigsum.org"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(errs), len(want), err)
+	}
+	for i, want := range want {
+		if got := errs[i].Error(); !strings.HasPrefix(got, want) {
+			t.Errorf("error %d = %q, want prefix %q", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// PolicyFile is a structured representation of a witness policy, for use in
+// configuration formats such as JSON, with encoding/json, and YAML, with
+// gopkg.in/yaml.v3. Each witness, group and quorum corresponds to a line of
+// the text format parsed by ParsePolicy.
+//
+// Log lines, which ParsePolicy ignores, and comments are not represented.
+type PolicyFile struct {
+	Witnesses []PolicyWitness `json:"witnesses,omitempty" yaml:"witnesses,omitempty"`
+	Groups    []PolicyGroup   `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Quorum is the name of the component which must be satisfied, or "none".
+	Quorum string `json:"quorum" yaml:"quorum"`
+}
+
+// PolicyWitness describes a witness line of a policy.
+type PolicyWitness struct {
+	Name string `json:"name" yaml:"name"`
+	VKey string `json:"vkey" yaml:"vkey"`
+	URL  string `json:"url" yaml:"url"`
+
+	// The remaining fields are the options constraining the validity of the
+	// witness key, and are unset if the corresponding option is not given.
+	// yaml.v3 has no omitzero option, but omitempty omits a time.Time whose
+	// IsZero method returns true, which is what omitzero does for JSON.
+	MinSize   uint64    `json:"min-size,omitempty" yaml:"min-size,omitempty"`
+	MaxSize   uint64    `json:"max-size,omitempty" yaml:"max-size,omitempty"`
+	NotBefore time.Time `json:"not-before,omitzero" yaml:"not-before,omitempty"`
+	NotAfter  time.Time `json:"not-after,omitzero" yaml:"not-after,omitempty"`
+}
+
+// PolicyGroup describes a group line of a policy.
+type PolicyGroup struct {
+	Name string `json:"name" yaml:"name"`
+	// Threshold is written as in the text format: "any", "all", a number of
+	// children, or "weight>=N" for a weighted group.
+	Threshold string        `json:"threshold" yaml:"threshold"`
+	Children  []PolicyChild `json:"children" yaml:"children"`
+}
+
+// PolicyChild is a member of a PolicyGroup.
+type PolicyChild struct {
+	// Name is the name of a witness or group defined earlier in the policy.
+	Name string `json:"name" yaml:"name"`
+	// Weight is the weight of the child in a weighted group, or 0 if no
+	// weight is given, in which case it weighs 1.
+	Weight int `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// PolicyFileError describes a problem with an element of a PolicyFile.
+type PolicyFileError struct {
+	// Path identifies the element at fault, such as "groups[1]" or
+	// "groups[1].children[0]".
+	Path string
+	Err  error
+}
+
+func (e *PolicyFileError) Error() string { return fmt.Sprintf("%s: %v", e.Path, e.Err) }
+
+func (e *PolicyFileError) Unwrap() error { return e.Err }
+
+// ParsePolicyFile parses a text policy, as described by ParsePolicy, into its
+// structured representation. The policy is validated by ParsePolicy, and any
+// errors are as returned by it.
+func ParsePolicyFile(p []byte) (*PolicyFile, error) {
+	if _, err := ParsePolicy(p); err != nil {
+		return nil, err
+	}
+	f := &PolicyFile{}
+	scanner := bufio.NewScanner(bytes.NewBuffer(p))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields, _ := splitFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "witness":
+			w := PolicyWitness{Name: fields[1], VKey: fields[2], URL: fields[3]}
+			if opts := fields[4:]; len(opts) > 0 {
+				v, err := parseValidity(opts)
+				if err != nil {
+					return nil, err
+				}
+				w.MinSize, w.MaxSize, w.NotBefore, w.NotAfter = v.MinTreeSize, v.MaxTreeSize, v.NotBefore, v.NotAfter
+			}
+			f.Witnesses = append(f.Witnesses, w)
+		case "group":
+			g := PolicyGroup{Name: fields[1], Threshold: fields[2], Children: make([]PolicyChild, 0, len(fields)-3)}
+			weighted := strings.HasPrefix(g.Threshold, weightPrefix)
+			for _, c := range fields[3:] {
+				child := PolicyChild{Name: c}
+				if weighted && strings.Contains(c, ":") {
+					var err error
+					if child.Name, child.Weight, err = parseWeightedChild(c); err != nil {
+						return nil, err
+					}
+				}
+				g.Children = append(g.Children, child)
+			}
+			f.Groups = append(f.Groups, g)
+		case "quorum":
+			f.Quorum = fields[1]
+		}
+	}
+	return f, scanner.Err()
+}
+
+// Text returns the policy in the text format parsed by ParsePolicy, with the
+// witnesses followed by the groups and then the quorum.
+//
+// The policy is validated as by ParsePolicy. Problems found are returned
+// together, each as a *PolicyFileError identifying the element at fault.
+func (f *PolicyFile) Text() ([]byte, error) {
+	var b bytes.Buffer
+	// paths holds the path of the element described by each line of b.
+	var paths []string
+	var errs []error
+	fail := func(path string, err error) {
+		errs = append(errs, &PolicyFileError{Path: path, Err: err})
+	}
+	// field checks that s can be written as a single field of a line.
+	field := func(path, what, s string) string {
+		if s == "" || strings.ContainsFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '#' }) {
+			fail(path, fmt.Errorf("invalid %s %q: must be non-empty and contain no whitespace or #", what, s))
+		}
+		return s
+	}
+
+	for i, w := range f.Witnesses {
+		path := fmt.Sprintf("witnesses[%d]", i)
+		fields := []string{"witness", field(path, "name", w.Name), field(path, "vkey", w.VKey), field(path, "URL", w.URL)}
+		if w.MinSize > 0 {
+			fields = append(fields, "min-size="+strconv.FormatUint(w.MinSize, 10))
+		}
+		if w.MaxSize > 0 {
+			fields = append(fields, "max-size="+strconv.FormatUint(w.MaxSize, 10))
+		}
+		if !w.NotBefore.IsZero() {
+			fields = append(fields, "not-before="+w.NotBefore.Format(time.RFC3339Nano))
+		}
+		if !w.NotAfter.IsZero() {
+			fields = append(fields, "not-after="+w.NotAfter.Format(time.RFC3339Nano))
+		}
+		fmt.Fprintln(&b, strings.Join(fields, " "))
+		paths = append(paths, path)
+	}
+	for i, g := range f.Groups {
+		path := fmt.Sprintf("groups[%d]", i)
+		fields := []string{"group", field(path, "name", g.Name), field(path, "threshold", g.Threshold)}
+		weighted := strings.HasPrefix(g.Threshold, weightPrefix)
+		for j, c := range g.Children {
+			cPath := fmt.Sprintf("%s.children[%d]", path, j)
+			name := field(cPath, "name", c.Name)
+			switch {
+			case c.Weight < 0:
+				fail(cPath, errors.New("weight must be at least 1"))
+			case c.Weight > 0 && !weighted:
+				fail(cPath, fmt.Errorf("weight given for member of unweighted group %q", g.Name))
+			case c.Weight > 0:
+				name += ":" + strconv.Itoa(c.Weight)
+			}
+			fields = append(fields, name)
+		}
+		fmt.Fprintln(&b, strings.Join(fields, " "))
+		paths = append(paths, path)
+	}
+	if f.Quorum == "" {
+		fail("quorum", ErrNoQuorum)
+	} else {
+		fmt.Fprintln(&b, "quorum", field("quorum", "quorum", f.Quorum))
+		paths = append(paths, "quorum")
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if _, err := ParsePolicy(b.Bytes()); err != nil {
+		var pErrs ParseErrors
+		if !errors.As(err, &pErrs) {
+			return nil, err
+		}
+		for _, pErr := range pErrs {
+			fail(paths[pErr.Line-1], pErr.Err)
+		}
+		return nil, errors.Join(errs...)
+	}
+	return b.Bytes(), nil
+}
+
+// Group returns the group which the policy requires to be satisfied, as
+// returned by ParsePolicy for the text form of the policy.
+func (f *PolicyFile) Group() (Group, error) {
+	p, err := f.Text()
+	if err != nil {
+		return Group{}, err
+	}
+	return ParsePolicy(p)
+}
@@ -0,0 +1,303 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+const textPolicy = `# A policy.
+witness w1 ` + wit1_vkey + ` https://w1.example.com/ max-size=100
+witness w2 ` + wit2_vkey + ` https://w2.example.com/ not-before=2026-01-02T03:04:05Z
+group g1 weight>=3 w1:2 w2
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+log example.com/log example.com+abcdef01+AAAA
+group g2 any g1 w3
+quorum g2
+`
+
+// canonicalPolicy is textPolicy as written by PolicyFile.Text.
+const canonicalPolicy = `witness w1 ` + wit1_vkey + ` https://w1.example.com/ max-size=100
+witness w2 ` + wit2_vkey + ` https://w2.example.com/ not-before=2026-01-02T03:04:05Z
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+group g1 weight>=3 w1:2 w2
+group g2 any g1 w3
+quorum g2
+`
+
+func TestParsePolicyFile(t *testing.T) {
+	got, err := ParsePolicyFile([]byte(textPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicyFile: %v", err)
+	}
+	want := &PolicyFile{
+		Witnesses: []PolicyWitness{
+			{Name: "w1", VKey: wit1_vkey, URL: "https://w1.example.com/", MaxSize: 100},
+			{Name: "w2", VKey: wit2_vkey, URL: "https://w2.example.com/", NotBefore: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+			{Name: "w3", VKey: wit3_vkey, URL: "https://w3.example.com/"},
+		},
+		Groups: []PolicyGroup{
+			{Name: "g1", Threshold: "weight>=3", Children: []PolicyChild{{Name: "w1", Weight: 2}, {Name: "w2"}}},
+			{Name: "g2", Threshold: "any", Children: []PolicyChild{{Name: "g1"}, {Name: "w3"}}},
+		},
+		Quorum: "g2",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParsePolicyFile diff (-want +got):\n%s", diff)
+	}
+
+	if _, err := ParsePolicyFile([]byte("witness w1 " + wit1_vkey + " https://w1.example.com/\ngroup g1 any w2\nquorum g1\n")); !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("ParsePolicyFile(invalid) = %v, want %v", err, ErrUnknownComponent)
+	}
+}
+
+func TestPolicyFile_RoundTrip(t *testing.T) {
+	want, err := ParsePolicy([]byte(textPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	var wantSets [][]string
+	for s := range want.MinimalSatisfyingSets() {
+		wantSets = append(wantSets, witnessNames(s))
+	}
+
+	for _, c := range []struct {
+		name      string
+		marshal   func(any) ([]byte, error)
+		unmarshal func([]byte, any) error
+	}{
+		{name: "JSON", marshal: json.Marshal, unmarshal: json.Unmarshal},
+		{name: "YAML", marshal: yaml.Marshal, unmarshal: yaml.Unmarshal},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := ParsePolicyFile([]byte(textPolicy))
+			if err != nil {
+				t.Fatalf("ParsePolicyFile: %v", err)
+			}
+			b, err := c.marshal(f)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var decoded PolicyFile
+			if err := c.unmarshal(b, &decoded); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", b, err)
+			}
+			if diff := cmp.Diff(f, &decoded); diff != "" {
+				t.Errorf("Unmarshal diff (-want +got):\n%s", diff)
+			}
+			text, err := decoded.Text()
+			if err != nil {
+				t.Fatalf("Text: %v", err)
+			}
+			if diff := cmp.Diff(canonicalPolicy, string(text)); diff != "" {
+				t.Errorf("Text diff (-want +got):\n%s", diff)
+			}
+
+			g, err := decoded.Group()
+			if err != nil {
+				t.Fatalf("Group: %v", err)
+			}
+			var gotSets [][]string
+			for s := range g.MinimalSatisfyingSets() {
+				gotSets = append(gotSets, witnessNames(s))
+			}
+			if diff := cmp.Diff(wantSets, gotSets); diff != "" {
+				t.Errorf("Group satisfying sets diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicyFile_JSON(t *testing.T) {
+	j := `{
+  "witnesses": [
+    {"name": "w1", "vkey": "` + wit1_vkey + `", "url": "https://w1.example.com/", "min-size": 10},
+    {"name": "w2", "vkey": "` + wit2_vkey + `", "url": "https://w2.example.com/"}
+  ],
+  "groups": [
+    {"name": "g1", "threshold": "all", "children": [{"name": "w1"}, {"name": "w2"}]}
+  ],
+  "quorum": "g1"
+}`
+	var f PolicyFile
+	if err := json.Unmarshal([]byte(j), &f); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	text, err := f.Text()
+	if err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	want := "witness w1 " + wit1_vkey + " https://w1.example.com/ min-size=10\n" +
+		"witness w2 " + wit2_vkey + " https://w2.example.com/\n" +
+		"group g1 all w1 w2\n" +
+		"quorum g1\n"
+	if diff := cmp.Diff(want, string(text)); diff != "" {
+		t.Errorf("Text diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestPolicyFile_YAML(t *testing.T) {
+	y := `witnesses:
+  - name: w1
+    vkey: ` + wit1_vkey + `
+    url: https://w1.example.com/
+    min-size: 10
+  - name: w2
+    vkey: ` + wit2_vkey + `
+    url: https://w2.example.com/
+    not-after: 2027-01-02T03:04:05Z
+groups:
+  - name: g1
+    threshold: weight>=2
+    children:
+      - name: w1
+        weight: 2
+      - name: w2
+quorum: g1
+`
+	var f PolicyFile
+	if err := yaml.Unmarshal([]byte(y), &f); err != nil {
+		t.Fatalf("yaml.Unmarshal: %v", err)
+	}
+	text, err := f.Text()
+	if err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	want := "witness w1 " + wit1_vkey + " https://w1.example.com/ min-size=10\n" +
+		"witness w2 " + wit2_vkey + " https://w2.example.com/ not-after=2027-01-02T03:04:05Z\n" +
+		"group g1 weight>=2 w1:2 w2\n" +
+		"quorum g1\n"
+	if diff := cmp.Diff(want, string(text)); diff != "" {
+		t.Errorf("Text diff (-want +got):\n%s", diff)
+	}
+
+	// Unset options are omitted, as they are from JSON.
+	b, err := yaml.Marshal(PolicyWitness{Name: "w1", VKey: wit1_vkey, URL: "https://w1.example.com/"})
+	if err != nil {
+		t.Fatalf("yaml.Marshal: %v", err)
+	}
+	for _, opt := range []string{"min-size", "max-size", "not-before", "not-after"} {
+		if strings.Contains(string(b), opt) {
+			t.Errorf("yaml.Marshal of witness without options = %q, want no %s", b, opt)
+		}
+	}
+
+	// A YAML policy is validated in the same way as the equivalent text.
+	invalid := strings.Replace(y, "      - name: w2\n", "      - name: w3\n", 1)
+	if err := yaml.Unmarshal([]byte(invalid), &f); err != nil {
+		t.Fatalf("yaml.Unmarshal: %v", err)
+	}
+	_, err = f.Group()
+	if _, textErr := ParsePolicy([]byte(strings.Replace(want, " w2\nquorum", " w3\nquorum", 1))); !errors.Is(textErr, ErrUnknownComponent) {
+		t.Fatalf("ParsePolicy(invalid) = %v, want %v", textErr, ErrUnknownComponent)
+	}
+	var fErr *PolicyFileError
+	if !errors.Is(err, ErrUnknownComponent) || !errors.As(err, &fErr) || fErr.Path != "groups[0]" {
+		t.Errorf("Group() of invalid YAML policy = %v, want error at groups[0] wrapping %v", err, ErrUnknownComponent)
+	}
+}
+
+func TestPolicyFile_Errors(t *testing.T) {
+	w1 := PolicyWitness{Name: "w1", VKey: wit1_vkey, URL: "https://w1.example.com/"}
+	for _, test := range []struct {
+		desc      string
+		f         PolicyFile
+		wantErrs  []string
+		wantErrIs error
+	}{
+		{
+			desc:      "no quorum",
+			f:         PolicyFile{Witnesses: []PolicyWitness{w1}},
+			wantErrs:  []string{"quorum: policy file must define a quorum"},
+			wantErrIs: ErrNoQuorum,
+		}, {
+			desc: "unknown child",
+			f: PolicyFile{
+				Witnesses: []PolicyWitness{w1},
+				Groups:    []PolicyGroup{{Name: "g1", Threshold: "any", Children: []PolicyChild{{Name: "w1"}, {Name: "w2"}}}},
+				Quorum:    "g1",
+			},
+			wantErrs:  []string{`groups[0]: unknown component "w2" in group definition`},
+			wantErrIs: ErrUnknownComponent,
+		}, {
+			desc: "several problems",
+			f: PolicyFile{
+				Witnesses: []PolicyWitness{w1, {Name: "w2", VKey: "bad-vkey", URL: "https://w2.example.com/"}, w1},
+				Groups:    []PolicyGroup{{Name: "g1", Threshold: "lots", Children: []PolicyChild{{Name: "w1"}}}},
+				Quorum:    "g2",
+			},
+			wantErrs: []string{
+				`witnesses[1]: invalid witness vkey "bad-vkey"`,
+				`witnesses[2]: duplicate component name: "w1"`,
+				`groups[0]: invalid threshold "lots"`,
+				`quorum: quorum component "g2" not found`,
+			},
+			wantErrIs: ErrDuplicateComponent,
+		}, {
+			desc: "unencodable fields",
+			f: PolicyFile{
+				Witnesses: []PolicyWitness{{Name: "w 1", VKey: wit1_vkey, URL: "https://w1.example.com/#frag"}},
+				Groups:    []PolicyGroup{{Name: "g1", Threshold: "any", Children: []PolicyChild{{Name: ""}}}},
+				Quorum:    "g1",
+			},
+			wantErrs: []string{
+				`witnesses[0]: invalid name "w 1"`,
+				`witnesses[0]: invalid URL "https://w1.example.com/#frag"`,
+				`groups[0].children[0]: invalid name ""`,
+			},
+		}, {
+			desc: "weight in unweighted group",
+			f: PolicyFile{
+				Witnesses: []PolicyWitness{w1},
+				Groups:    []PolicyGroup{{Name: "g1", Threshold: "any", Children: []PolicyChild{{Name: "w1", Weight: 2}}}},
+				Quorum:    "g1",
+			},
+			wantErrs: []string{`groups[0].children[0]: weight given for member of unweighted group "g1"`},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := test.f.Text()
+			if err == nil {
+				t.Fatal("Text() succeeded, want error")
+			}
+			got := strings.Split(err.Error(), "\n")
+			if len(got) != len(test.wantErrs) {
+				t.Fatalf("Text() = %v, want %d errors", err, len(test.wantErrs))
+			}
+			for i, want := range test.wantErrs {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("error %d = %q, want prefix %q", i, got[i], want)
+				}
+			}
+			var fErr *PolicyFileError
+			if !errors.As(err, &fErr) {
+				t.Errorf("Text() = %v, want *PolicyFileError", err)
+			}
+			if test.wantErrIs != nil && !errors.Is(err, test.wantErrIs) {
+				t.Errorf("Text() = %v, want %v", err, test.wantErrIs)
+			}
+			if _, gErr := test.f.Group(); gErr == nil {
+				t.Error("Group() succeeded, want error")
+			}
+		})
+	}
+}
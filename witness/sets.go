@@ -0,0 +1,213 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"strings"
+)
+
+// id identifies the witness by its key and URL, so that the same witness
+// reached through different groups is counted once.
+func (w Witness) id() string {
+	return fmt.Sprintf("%s+%08x %s", w.Key.Name(), w.Key.KeyHash(), w.URL)
+}
+
+func (w Witness) satisfiableWith(available func(Witness) bool) bool {
+	return available(w)
+}
+
+func (w Witness) satisfyingSets() iter.Seq[[]Witness] {
+	return func(yield func([]Witness) bool) {
+		yield([]Witness{w})
+	}
+}
+
+func (w Witness) witnesses(yield func(Witness) bool) bool {
+	return yield(w)
+}
+
+// Witnesses returns the distinct witnesses in the group and its subgroups, in
+// the order they are first reached.
+func (wg Group) Witnesses() []Witness {
+	var ws []Witness
+	seen := make(map[string]bool)
+	wg.witnesses(func(w Witness) bool {
+		if !seen[w.id()] {
+			seen[w.id()] = true
+			ws = append(ws, w)
+		}
+		return true
+	})
+	return ws
+}
+
+func (wg Group) witnesses(yield func(Witness) bool) bool {
+	for _, c := range wg.Components {
+		if !c.witnesses(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// SatisfiableWithout returns true if cosignatures from the witnesses in the
+// group other than those given are sufficient to satisfy the group, e.g. to
+// check whether a quorum can still be met while some witnesses are down.
+//
+// Witnesses are matched by their key and URL.
+func (wg Group) SatisfiableWithout(unavailable ...Witness) bool {
+	down := make(map[string]bool, len(unavailable))
+	for _, w := range unavailable {
+		down[w.id()] = true
+	}
+	return wg.satisfiableWith(func(w Witness) bool { return !down[w.id()] })
+}
+
+func (wg Group) satisfiableWith(available func(Witness) bool) bool {
+	satisfied := 0
+	for i, c := range wg.Components {
+		if satisfied >= wg.N {
+			break
+		}
+		if c.satisfiableWith(available) {
+			satisfied += wg.weight(i)
+		}
+	}
+	return satisfied >= wg.N
+}
+
+// MinimalSatisfyingSets returns an iterator over the minimal sets of
+// witnesses whose cosignatures satisfy the group. A set is minimal if no
+// witness can be removed from it without the group ceasing to be satisfied.
+// Each set is yielded once, with its witnesses in the order they appear in
+// the group.
+//
+// The number of minimal sets grows combinatorially with the size of the
+// policy, so callers with large policies should stop iterating once they
+// have seen as many sets as they need.
+func (wg Group) MinimalSatisfyingSets() iter.Seq[[]Witness] {
+	order := make(map[string]int)
+	for i, w := range wg.Witnesses() {
+		order[w.id()] = i
+	}
+	return func(yield func([]Witness) bool) {
+		seen := make(map[string]bool)
+		for set := range wg.satisfyingSets() {
+			// Sets built from witnesses which appear in more than one
+			// subgroup may contain duplicates, be found more than once, or
+			// not be minimal.
+			set = slices.CompactFunc(sortedWitnesses(set, order), func(a, b Witness) bool { return a.id() == b.id() })
+			key := setKey(set)
+			if seen[key] || !wg.isMinimal(set) {
+				continue
+			}
+			seen[key] = true
+			if !yield(set) {
+				return
+			}
+		}
+	}
+}
+
+// satisfyingSets yields sets of witnesses which satisfy the group. For groups
+// in which no witness is reachable in more than one way, these are exactly
+// the minimal satisfying sets.
+func (wg Group) satisfyingSets() iter.Seq[[]Witness] {
+	return func(yield func([]Witness) bool) {
+		// For each minimal combination of components which meets the
+		// threshold, yield the union of every choice of satisfying set for
+		// each of those components.
+		var product func(cs []policyComponent, acc []Witness) bool
+		product = func(cs []policyComponent, acc []Witness) bool {
+			if len(cs) == 0 {
+				return yield(slices.Clone(acc))
+			}
+			for s := range cs[0].satisfyingSets() {
+				if !product(cs[1:], append(acc, s...)) {
+					return false
+				}
+			}
+			return true
+		}
+		for chosen := range wg.combinations() {
+			if !product(chosen, nil) {
+				return
+			}
+		}
+	}
+}
+
+// isMinimal returns true if the set satisfies the group, and no witness can
+// be removed from it without the group ceasing to be satisfied.
+func (wg Group) isMinimal(set []Witness) bool {
+	in := make(map[string]bool, len(set))
+	for _, w := range set {
+		in[w.id()] = true
+	}
+	if !wg.satisfiableWith(func(w Witness) bool { return in[w.id()] }) {
+		return false
+	}
+	for _, removed := range set {
+		if wg.satisfiableWith(func(w Witness) bool { return in[w.id()] && w.id() != removed.id() }) {
+			return false
+		}
+	}
+	return true
+}
+
+// combinations yields each combination of the group's components whose total
+// weight meets the threshold, and from which no component can be removed
+// without it falling below the threshold. The order of the components is
+// preserved. For groups without weights, these are the combinations of N
+// components.
+func (wg Group) combinations() iter.Seq[[]policyComponent] {
+	return func(yield func([]policyComponent) bool) {
+		var choose func(start, weight, minWeight int, acc []policyComponent) bool
+		choose = func(start, weight, minWeight int, acc []policyComponent) bool {
+			if weight >= wg.N {
+				if weight-minWeight >= wg.N {
+					return true
+				}
+				return yield(slices.Clone(acc))
+			}
+			for i := start; i < len(wg.Components); i++ {
+				w := wg.weight(i)
+				if !choose(i+1, weight+w, min(minWeight, w), append(acc, wg.Components[i])) {
+					return false
+				}
+			}
+			return true
+		}
+		choose(0, 0, math.MaxInt, nil)
+	}
+}
+
+// sortedWitnesses sorts the set by the given order of witness IDs.
+func sortedWitnesses(set []Witness, order map[string]int) []Witness {
+	slices.SortStableFunc(set, func(a, b Witness) int { return order[a.id()] - order[b.id()] })
+	return set
+}
+
+func setKey(set []Witness) string {
+	ids := make([]string, len(set))
+	for i, w := range set {
+		ids[i] = w.id()
+	}
+	return strings.Join(ids, "\n")
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func witnessNames(ws []Witness) []string {
+	names := make([]string, len(ws))
+	for i, w := range ws {
+		names[i] = w.Key.Name()
+	}
+	return names
+}
+
+func TestGroup_MinimalSatisfyingSets(t *testing.T) {
+	for _, test := range []struct {
+		desc  string
+		group Group
+		want  [][]string
+	}{
+		{
+			desc:  "empty",
+			group: NewGroup(0),
+			want:  [][]string{{}},
+		}, {
+			desc:  "single witness",
+			group: NewGroup(1, wit1),
+			want:  [][]string{{"Wit1"}},
+		}, {
+			desc:  "threshold",
+			group: NewGroup(2, wit1, wit2, wit3),
+			want:  [][]string{{"Wit1", "Wit2"}, {"Wit1", "Wit3"}, {"Wit2", "Wit3"}},
+		}, {
+			desc:  "nested",
+			group: NewGroup(1, NewGroup(2, wit1, wit2), wit3),
+			want:  [][]string{{"Wit1", "Wit2"}, {"Wit3"}},
+		}, {
+			desc:  "zero threshold subgroup",
+			group: NewGroup(2, NewGroup(0, wit1), wit2, wit3),
+			want:  [][]string{{"Wit2"}, {"Wit3"}},
+		}, {
+			desc:  "weighted",
+			group: NewWeightedGroup(3, []int{2, 1, 2}, wit1, wit2, wit3),
+			want:  [][]string{{"Wit1", "Wit2"}, {"Wit1", "Wit3"}, {"Wit2", "Wit3"}},
+		}, {
+			desc:  "weighted with dominant member",
+			group: NewWeightedGroup(3, []int{3, 1, 2}, wit1, wit2, wit3),
+			want:  [][]string{{"Wit1"}, {"Wit2", "Wit3"}},
+		}, {
+			desc:  "shared witness",
+			group: NewGroup(2, NewGroup(1, wit1, wit2), NewGroup(1, wit1, wit3)),
+			want:  [][]string{{"Wit1"}, {"Wit2", "Wit3"}},
+		}, {
+			desc:  "shared witness found twice",
+			group: NewGroup(1, NewGroup(2, wit1, wit2), NewGroup(2, wit2, wit1)),
+			want:  [][]string{{"Wit1", "Wit2"}},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			var got [][]string
+			for set := range test.group.MinimalSatisfyingSets() {
+				got = append(got, witnessNames(set))
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("MinimalSatisfyingSets() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroup_MinimalSatisfyingSets_Stop(t *testing.T) {
+	n := 0
+	for range NewGroup(2, wit1, wit2, wit3).MinimalSatisfyingSets() {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("got %d sets, want 2", n)
+	}
+}
+
+func TestGroup_SatisfiableWithout(t *testing.T) {
+	nested := NewGroup(2, NewGroup(1, wit1, wit2), NewGroup(1, wit1, wit3))
+	for _, test := range []struct {
+		desc        string
+		group       Group
+		unavailable []Witness
+		want        bool
+	}{
+		{
+			desc:  "all available",
+			group: NewGroup(2, wit1, wit2, wit3),
+			want:  true,
+		}, {
+			desc:        "one down",
+			group:       NewGroup(2, wit1, wit2, wit3),
+			unavailable: []Witness{wit1},
+			want:        true,
+		}, {
+			desc:        "two down",
+			group:       NewGroup(2, wit1, wit2, wit3),
+			unavailable: []Witness{wit1, wit3},
+		}, {
+			desc:        "shared witness down",
+			group:       nested,
+			unavailable: []Witness{wit1},
+			want:        true,
+		}, {
+			desc:        "shared witness and another down",
+			group:       nested,
+			unavailable: []Witness{wit1, wit2},
+		}, {
+			desc:        "weighted, heavy witness down",
+			group:       NewWeightedGroup(3, []int{2, 1, 1}, wit1, wit2, wit3),
+			unavailable: []Witness{wit1},
+		}, {
+			desc:        "weighted, light witness down",
+			group:       NewWeightedGroup(3, []int{2, 1, 1}, wit1, wit2, wit3),
+			unavailable: []Witness{wit2},
+			want:        true,
+		}, {
+			desc:        "empty group",
+			group:       NewGroup(0),
+			unavailable: []Witness{wit1},
+			want:        true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := test.group.SatisfiableWithout(test.unavailable...); got != test.want {
+				t.Errorf("SatisfiableWithout(%v) = %t, want %t", witnessNames(test.unavailable), got, test.want)
+			}
+		})
+	}
+}
+
+func TestGroup_Witnesses(t *testing.T) {
+	g := NewGroup(2, NewGroup(1, wit1, wit2), NewGroup(1, wit1, wit3))
+	if diff := cmp.Diff([]string{"Wit1", "Wit2", "Wit3"}, witnessNames(g.Witnesses())); diff != "" {
+		t.Errorf("Witnesses() diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	f_note "github.com/transparency-dev/formats/note"
+)
+
+// SubtreeCapable returns true if the witness key can verify cosignatures over
+// st. ML-DSA-44 keys from New can verify cosignatures over any subtree, but
+// Ed25519 keys only over subtrees starting at the beginning of the log, see
+// f_note.SupportsSubtree.
+func (w Witness) SubtreeCapable(st f_note.Subtree) bool {
+	v, ok := w.Key.(f_note.SubtreeVerifier)
+	return ok && f_note.SupportsSubtree(v, st)
+}
+
+// SatisfiedBySubtree returns true if sigs holds a valid cosignature over the
+// subtree from this witness.
+//
+// This always returns false if the witness is not SubtreeCapable for st.
+func (w Witness) SatisfiedBySubtree(st f_note.Subtree, sigs []f_note.SubtreeSignature) bool {
+	v, ok := w.Key.(f_note.SubtreeVerifier)
+	if !ok {
+		return false
+	}
+	for _, sig := range sigs {
+		if f_note.VerifySubtreeSignature(v, st, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// SatisfiedBySubtree returns true if sigs holds valid cosignatures over the
+// subtree from enough witnesses to satisfy the group, applying the same
+// thresholds as Satisfied does for checkpoints.
+//
+// Witnesses which are not SubtreeCapable for st never contribute to the
+// group, see SubtreeIncapable.
+func (wg Group) SatisfiedBySubtree(st f_note.Subtree, sigs []f_note.SubtreeSignature) bool {
+	satisfied := make(map[string]bool)
+	return wg.satisfiableWith(func(w Witness) bool {
+		id := w.id()
+		s, ok := satisfied[id]
+		if !ok {
+			s = w.SatisfiedBySubtree(st, sigs)
+			satisfied[id] = s
+		}
+		return s
+	})
+}
+
+// SubtreeIncapable returns the distinct witnesses in the group and its
+// subgroups which cannot verify cosignatures over st, and so can never help
+// to satisfy the group with SatisfiedBySubtree for st.
+func (wg Group) SubtreeIncapable(st f_note.Subtree) []Witness {
+	var ws []Witness
+	for _, w := range wg.Witnesses() {
+		if !w.SubtreeCapable(st) {
+			ws = append(ws, w)
+		}
+	}
+	return ws
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	f_note "github.com/transparency-dev/formats/note"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// mldsaWitness returns an ML-DSA witness with the given name, and a function
+// which returns its cosignature over a subtree.
+func mldsaWitness(t *testing.T, name string) (Witness, func(f_note.Subtree) f_note.SubtreeSignature) {
+	t.Helper()
+	skey, vkey, err := f_note.GenerateMLDSAKey(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := f_note.NewMLDSASigner(skey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(vkey, directURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, func(st f_note.Subtree) f_note.SubtreeSignature {
+		t.Helper()
+		sig, err := s.SignSubtree(0, st.Origin, st.Start, st.End, st.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f_note.SubtreeSignature{Name: s.Name(), Hash: s.KeyHash(), Sig: sig}
+	}
+}
+
+// plainWitness returns a witness whose key cannot verify subtree cosignatures.
+func plainWitness(t *testing.T, vkey string) Witness {
+	t.Helper()
+	v, err := note.NewVerifier(vkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Witness{Key: v, URL: directURL.String()}
+}
+
+func TestGroup_SatisfiedBySubtree(t *testing.T) {
+	plain1, plain2 := plainWitness(t, wit1_vkey), plainWitness(t, wit2_vkey)
+	m1, sign1 := mldsaWitness(t, "m1")
+	m2, sign2 := mldsaWitness(t, "m2")
+	m3, sign3 := mldsaWitness(t, "m3")
+	st := f_note.Subtree{Origin: "example.com/log", Start: 256, End: 512, Hash: make([]byte, 32)}
+	other := f_note.Subtree{Origin: st.Origin, Start: 0, End: 512, Hash: st.Hash}
+
+	for _, test := range []struct {
+		desc  string
+		group Group
+		sigs  []f_note.SubtreeSignature
+		want  bool
+	}{
+		{
+			desc:  "threshold met",
+			group: NewGroup(2, m1, m2, m3),
+			sigs:  []f_note.SubtreeSignature{sign1(st), sign3(st)},
+			want:  true,
+		}, {
+			desc:  "threshold not met",
+			group: NewGroup(2, m1, m2, m3),
+			sigs:  []f_note.SubtreeSignature{sign1(st)},
+		}, {
+			desc:  "signatures over another subtree",
+			group: NewGroup(2, m1, m2, m3),
+			sigs:  []f_note.SubtreeSignature{sign1(st), sign2(other)},
+		}, {
+			desc:  "repeated signature",
+			group: NewGroup(2, m1, m2, m3),
+			sigs:  []f_note.SubtreeSignature{sign1(st), sign1(st)},
+		}, {
+			desc:  "weighted",
+			group: NewWeightedGroup(3, []int{2, 1, 1}, m1, m2, m3),
+			sigs:  []f_note.SubtreeSignature{sign1(st), sign2(st)},
+			want:  true,
+		}, {
+			desc:  "nested",
+			group: NewGroup(2, NewGroup(1, m1, m2), NewGroup(1, m1, m3)),
+			sigs:  []f_note.SubtreeSignature{sign1(st)},
+			want:  true,
+		}, {
+			desc:  "non-subtree witnesses never satisfied",
+			group: NewGroup(2, m1, plain1, plain2),
+			sigs:  []f_note.SubtreeSignature{sign1(st)},
+		}, {
+			desc:  "non-subtree witnesses not needed",
+			group: NewGroup(1, m1, plain1),
+			sigs:  []f_note.SubtreeSignature{sign1(st)},
+			want:  true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := test.group.SatisfiedBySubtree(st, test.sigs); got != test.want {
+				t.Errorf("SatisfiedBySubtree() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGroup_SatisfiedBySubtree_Ed25519(t *testing.T) {
+	m1, signM1 := mldsaWitness(t, "m1")
+	s, err := f_note.NewSubtreeSignerForCosignatureV1(wit1_skey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGroup(2, m1, wit1)
+
+	// An Ed25519 witness can cosign a subtree starting at 0, as this is the
+	// checkpoint of the same size.
+	st := f_note.Subtree{Origin: "example.com/log", Start: 0, End: 512, Hash: make([]byte, 32)}
+	sig, err := s.SignSubtree(1234, st.Origin, st.Start, st.End, st.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs := []f_note.SubtreeSignature{signM1(st), {Name: s.Name(), Hash: s.KeyHash(), Sig: sig}}
+	if !g.SatisfiedBySubtree(st, sigs) {
+		t.Error("SatisfiedBySubtree() = false for subtree starting at 0, want true")
+	}
+
+	// The same signature does not cover any other subtree.
+	other := f_note.Subtree{Origin: st.Origin, Start: 256, End: 512, Hash: st.Hash}
+	if g.SatisfiedBySubtree(other, sigs) {
+		t.Error("SatisfiedBySubtree() = true for subtree not starting at 0, want false")
+	}
+}
+
+func TestGroup_SubtreeIncapable(t *testing.T) {
+	m1, _ := mldsaWitness(t, "m1")
+	plain1, plain2 := plainWitness(t, wit1_vkey), plainWitness(t, wit2_vkey)
+	st := f_note.Subtree{Origin: "example.com/log", Start: 0, End: 512, Hash: make([]byte, 32)}
+	g := NewGroup(2, NewGroup(1, m1, wit3, plain1), NewGroup(1, plain1, plain2))
+	if diff := cmp.Diff([]string{"Wit1", "Wit2"}, witnessNames(g.SubtreeIncapable(st))); diff != "" {
+		t.Errorf("SubtreeIncapable() diff (-want +got):\n%s", diff)
+	}
+	if !m1.SubtreeCapable(st) || !wit1.SubtreeCapable(st) || plain1.SubtreeCapable(st) {
+		t.Errorf("SubtreeCapable() = %t, %t, %t, want true, true, false", m1.SubtreeCapable(st), wit1.SubtreeCapable(st), plain1.SubtreeCapable(st))
+	}
+
+	// Ed25519 witnesses cannot verify cosignatures over subtrees which do
+	// not start at the beginning of the log.
+	st.Start = 256
+	if diff := cmp.Diff([]string{"Wit3", "Wit1", "Wit2"}, witnessNames(g.SubtreeIncapable(st))); diff != "" {
+		t.Errorf("SubtreeIncapable() diff (-want +got):\n%s", diff)
+	}
+	if !m1.SubtreeCapable(st) || wit1.SubtreeCapable(st) {
+		t.Errorf("SubtreeCapable() = %t, %t, want true, false", m1.SubtreeCapable(st), wit1.SubtreeCapable(st))
+	}
+}
+
+func TestGroup_SubtreeIncapable_Ed25519Policy(t *testing.T) {
+	policy := "witness w1 " + wit1_vkey + " https://w1.example.com/\n" +
+		"witness w2 " + wit2_vkey + " https://w2.example.com/ not-after=2030-01-01T00:00:00Z\n" +
+		"group g1 any w1 w2\n" +
+		"quorum g1\n"
+	g, err := ParsePolicy([]byte(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		desc  string
+		start uint64
+		want  []string
+	}{
+		{desc: "starting at 0", start: 0, want: []string{}},
+		{desc: "not starting at 0", start: 256, want: []string{"Wit1", "Wit2"}},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			st := f_note.Subtree{Origin: "example.com/log", Start: test.start, End: 512, Hash: make([]byte, 32)}
+			if diff := cmp.Diff(test.want, witnessNames(g.SubtreeIncapable(st))); diff != "" {
+				t.Errorf("SubtreeIncapable() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,10 +17,17 @@ package witness
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
+	"iter"
 	"net/url"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"maps"
 
@@ -28,6 +35,71 @@ import (
 	"golang.org/x/mod/sumdb/note"
 )
 
+// Errors wrapped by those returned from ParsePolicy.
+var (
+	// ErrNoQuorum is returned for a policy which does not define a quorum.
+	ErrNoQuorum = errors.New("policy file must define a quorum")
+	// ErrUnknownComponent is returned when a group or the quorum refers to a
+	// component which has not been defined.
+	ErrUnknownComponent = errors.New("unknown component")
+	// ErrDuplicateComponent is returned when a component name is defined more
+	// than once.
+	ErrDuplicateComponent = errors.New("duplicate component name")
+	// ErrForwardReference is returned when a group refers to a component
+	// which is only defined by a later line.
+	ErrForwardReference = errors.New("forward reference")
+)
+
+// ParseError describes a problem found when parsing a line of a policy or log
+// list.
+type ParseError struct {
+	// File is the name of the policy file containing the line, if the policy
+	// was read from a file system.
+	File string
+	// Line is the 1-based line number at fault.
+	Line int
+	// Column is the 1-based column, counted in bytes, at which the field at
+	// fault starts, or 0 if the problem is not with a particular field.
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.File != "" && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+	case e.File != "":
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	case e.Column > 0:
+		return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// ParseErrors holds every problem found when parsing a policy, in the order
+// they were found.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the individual errors, so that errors.Is and errors.As
+// consider each of them.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
 // policyComponent describes a component that makes up a policy. This is either a
 // single Witness, or a WitnessGroup.
 type policyComponent interface {
@@ -40,6 +112,18 @@ type policyComponent interface {
 	// the witness with a new checkpoint, to the value which is the verifier to check
 	// the response is well formed.
 	Endpoints() map[string]note.Verifier
+
+	// satisfiableWith returns true if cosignatures from the witnesses for
+	// which available returns true would satisfy this policy component.
+	satisfiableWith(available func(Witness) bool) bool
+
+	// satisfyingSets yields sets of witnesses whose cosignatures satisfy
+	// this policy component.
+	satisfyingSets() iter.Seq[[]Witness]
+
+	// witnesses yields each witness in this policy component, stopping if
+	// yield returns false. It returns false if it was stopped.
+	witnesses(yield func(Witness) bool) bool
 }
 
 // ParsePolicy creates a graph of witness objects that represents the
@@ -48,122 +132,529 @@ type policyComponent interface {
 // The policy structure is as described by [Sigsum's policy format](https://git.glasklar.is/sigsum/core/sigsum-go/-/blob/main/doc/policy.md)
 // but with the difference that the configured witness keys MUST be signature type `0x04` `vkey`s as specified
 // by C2SP [signed-note](https://github.com/C2SP/C2SP/blob/main/signed-note.md#verifier-keys).
+//
+// Witness lines may additionally carry options which restrict the
+// cosignatures accepted from the witness key, e.g.
+//
+//	witness w1-old <vkey> <url> not-after=2026-01-01T00:00:00Z
+//	witness w1-new <vkey> <url> not-before=2025-12-01T00:00:00Z
+//	group w1 any w1-old w1-new
+//
+// The supported options are min-size and max-size, which are inclusive bounds
+// on the checkpoint tree size, and not-before and not-after, which are
+// inclusive bounds in RFC 3339 format on the cosignature timestamp.
+//
+// Groups may also give their members different weights, in which case the
+// group is satisfied when the total weight of its satisfied members reaches
+// the threshold. Members without a weight count as 1, e.g.
+//
+//	group g1 weight>=3 vendor:2 w1 w2
+//
+// Parsing continues past problems with individual lines, so that every
+// problem is reported. These are returned as ParseErrors, which holds a
+// *ParseError with the line and column of each.
 func ParsePolicy(p []byte) (Group, error) {
-	scanner := bufio.NewScanner(bytes.NewBuffer(p))
-	components := make(map[string]policyComponent)
+	pol, err := parsePolicy(p)
+	if err != nil {
+		return Group{}, err
+	}
+	return pol.quorumGroup(), nil
+}
+
+// policy is a parsed policy file. Alongside the components it defines, it
+// retains their names and where they were defined, for use in analysing the
+// policy.
+type policy struct {
+	components map[string]policyComponent
+	witnesses  map[string]witnessDef
+	groups     map[string]groupDef
+	// names holds the component names in the order they were defined.
+	names        []string
+	quorum       string
+	quorumFile   string
+	quorumLine   int
+	quorumColumn int
+}
+
+// witnessDef describes a witness line of a policy.
+type witnessDef struct {
+	file string
+	line int
+	vkey string
+	w    Witness
+}
+
+// groupDef describes a group line of a policy.
+type groupDef struct {
+	file     string
+	line     int
+	n        int
+	weights  []int
+	children []string
+}
+
+// ParsePolicyFS parses the named policy file from fsys, as ParsePolicy does,
+// additionally allowing the policy to be composed from several files with
+// lines of the form
+//
+//	include <file>
+//
+// The included file is parsed in place of the include line, so the
+// components it defines may be referred to by the lines which follow, and
+// any quorum it defines is overridden by a later quorum line. Paths are
+// relative to the directory of the including file and must remain within
+// fsys.
+//
+// A file which has already been parsed is not parsed again, so fragments may
+// be shared by several files, but it is an error for a file to include itself,
+// directly or indirectly.
+//
+// Problems with the policy are returned as ParseErrors, as with ParsePolicy,
+// each naming the file in which it was found.
+func ParsePolicyFS(fsys fs.FS, name string) (Group, error) {
+	pol, err := parsePolicyFS(fsys, name)
+	if err != nil {
+		return Group{}, err
+	}
+	return pol.quorumGroup(), nil
+}
 
-	var quorumName string
+// parsePolicy parses a policy as described by ParsePolicy.
+func parsePolicy(p []byte) (*policy, error) {
+	pp := newPolicyParser(nil)
+	if err := pp.parseFile("", p); err != nil {
+		return nil, err
+	}
+	return pp.finish()
+}
+
+// parsePolicyFS parses a policy as described by ParsePolicyFS.
+func parsePolicyFS(fsys fs.FS, name string) (*policy, error) {
+	p, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	pp := newPolicyParser(fsys)
+	pp.including = []string{name}
+	if err := pp.parseFile(name, p); err != nil {
+		return nil, err
+	}
+	return pp.finish()
+}
+
+// policyParser builds a policy from one or more policy files, collecting the
+// problems found along the way.
+type policyParser struct {
+	pol *policy
+	// fsys is where included files are read from, or nil if includes are not
+	// supported.
+	fsys fs.FS
+	// including holds the files currently being parsed, outermost first.
+	including []string
+	// parsed holds the files which have been parsed.
+	parsed map[string]bool
+
+	// errs holds the problems found so far.
+	errs ParseErrors
+	// definedAt holds the location of each component definition seen so far,
+	// including those which turned out to be invalid.
+	definedAt map[string]string
+	// failed holds the names of components whose definitions were invalid,
+	// so that references to them are not reported as well.
+	failed map[string]bool
+	// unresolved holds the references to components which were not defined
+	// when they were referred to. Their errors are completed once every line
+	// has been parsed, when it is known whether they were defined later.
+	unresolved []unresolvedRef
+}
+
+// unresolvedRef is a reference to a component which was not defined when it
+// was referred to.
+type unresolvedRef struct {
+	name string
+	err  *ParseError
+}
+
+// errReported is returned when parsing a line for which the problems have
+// already been recorded.
+var errReported = errors.New("reported")
+
+// fieldError is an error relating to the field at the given index of a
+// policy line. Where fieldErrors are nested, the index of the inner error is
+// relative to that of the outer, so that functions parsing part of a line
+// needn't know where that part starts.
+type fieldError struct {
+	field int
+	err   error
+}
+
+func (e *fieldError) Error() string { return e.err.Error() }
+
+func (e *fieldError) Unwrap() error { return e.err }
+
+// atField returns err, marked as relating to the field at index i.
+func atField(i int, err error) error {
+	return &fieldError{field: i, err: err}
+}
+
+// fieldIndex returns the index of the field that err relates to.
+func fieldIndex(err error) int {
+	i := 0
+	for ; err != nil; err = errors.Unwrap(err) {
+		if fErr, ok := err.(*fieldError); ok {
+			i += fErr.field
+		}
+	}
+	return i
+}
+
+func newPolicyParser(fsys fs.FS) *policyParser {
+	return &policyParser{
+		pol: &policy{
+			components: make(map[string]policyComponent),
+			witnesses:  make(map[string]witnessDef),
+			groups:     make(map[string]groupDef),
+		},
+		fsys:      fsys,
+		parsed:    make(map[string]bool),
+		definedAt: make(map[string]string),
+		failed:    make(map[string]bool),
+	}
+}
+
+// splitFields splits a line into whitespace separated fields, returning the
+// 1-based column at which each starts.
+func splitFields(line string) ([]string, []int) {
+	var fields []string
+	var cols []int
+	start := -1
+	for i, r := range line + " " {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			fields = append(fields, line[start:i])
+			cols = append(cols, start+1)
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	return fields, cols
+}
+
+// location describes a line of a policy file for use in messages.
+func location(file string, line int) string {
+	if file != "" {
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+	return fmt.Sprintf("line %d", line)
+}
+
+// parseFile parses the lines of a policy file into the policy, recording any
+// problems found. The returned error is reserved for failures to read the
+// file.
+func (pp *policyParser) parseFile(file string, p []byte) error {
+	scanner := bufio.NewScanner(bytes.NewBuffer(p))
+	var lineNum int
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+		line := scanner.Text()
 		if i := strings.Index(line, "#"); i >= 0 {
 			line = line[:i]
 		}
-		if line == "" {
+		fields, cols := splitFields(line)
+		if len(fields) == 0 {
 			continue
 		}
+		if err := pp.parseLine(file, lineNum, fields, cols); err != nil && err != errReported {
+			pp.errs = append(pp.errs, &ParseError{File: file, Line: lineNum, Column: cols[min(fieldIndex(err), len(cols)-1)], Err: err})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	pp.parsed[file] = true
+	return nil
+}
 
-		switch fields := strings.Fields(line); fields[0] {
-		case "log":
-			// This keyword is important to clients who might use the policy file, but we don't need to know about it since
-			// we _are_ the log, so just ignore it.
-		case "witness":
-			// Strictly, the URL is optional so policy files can be used client-side, where they don't care about the URL.
-			// Given this function is parsing to create the graph structure which will be used by a Tessera log to witness
-			// new checkpoints we'll ignore that special case here.
-			if len(fields) != 4 {
-				return Group{}, fmt.Errorf("invalid witness definition: %q", line)
-			}
-			name, vkey, witnessURLStr := fields[1], fields[2], fields[3]
-			if isBadName(name) {
-				return Group{}, fmt.Errorf("invalid witness name %q", name)
-			}
-			if _, ok := components[name]; ok {
-				return Group{}, fmt.Errorf("duplicate component name: %q", name)
-			}
-			witnessURL, err := url.Parse(witnessURLStr)
-			if err != nil {
-				return Group{}, fmt.Errorf("invalid witness URL %q: %w", witnessURLStr, err)
-			}
-			w, err := New(vkey, witnessURL)
+// parseLine parses the fields of a line of a policy file into the policy.
+func (pp *policyParser) parseLine(file string, lineNum int, fields []string, cols []int) (err error) {
+	pol := pp.pol
+	components := pol.components
+
+	// define records the definition of a component with the given name, and
+	// ensures that it is recorded as failed if the definition is invalid.
+	define := func(name string) error {
+		if isBadName(name) {
+			return atField(1, fmt.Errorf("invalid %s name %q", fields[0], name))
+		}
+		if at, ok := pp.definedAt[name]; ok {
+			return atField(1, fmt.Errorf("%w: %q, already defined at %s", ErrDuplicateComponent, name, at))
+		}
+		pp.definedAt[name] = location(file, lineNum)
+		return nil
+	}
+	defer func() {
+		if err != nil && (fields[0] == "witness" || fields[0] == "group") && len(fields) > 1 {
+			pp.failed[fields[1]] = true
+		}
+	}()
+
+	switch fields[0] {
+	case "log":
+		// This keyword is important to clients who might use the policy file, but we don't need to know about it since
+		// we _are_ the log, so just ignore it.
+	case "witness":
+		// Strictly, the URL is optional so policy files can be used client-side, where they don't care about the URL.
+		// Given this function is parsing to create the graph structure which will be used by a Tessera log to witness
+		// new checkpoints we'll ignore that special case here.
+		//
+		// Any further fields are options constraining the validity of the witness key,
+		// allowing keys to be rotated by grouping the old and new keys together.
+		if len(fields) < 4 {
+			return atField(len(fields)-1, errors.New("witness definition must have a name, vkey and URL"))
+		}
+		name, vkey, witnessURLStr := fields[1], fields[2], fields[3]
+		if err := define(name); err != nil {
+			return err
+		}
+		witnessURL, err := url.Parse(witnessURLStr)
+		if err != nil {
+			return atField(3, fmt.Errorf("invalid witness URL %q: %w", witnessURLStr, err))
+		}
+		w, err := New(vkey, witnessURL)
+		if err != nil {
+			return atField(2, fmt.Errorf("invalid witness vkey %q: %w", vkey, err))
+		}
+		if opts := fields[4:]; len(opts) > 0 {
+			validity, err := parseValidity(opts)
 			if err != nil {
-				return Group{}, fmt.Errorf("invalid witness config %q: %w", line, err)
-			}
-			components[name] = w
-		case "group":
-			if len(fields) < 3 {
-				return Group{}, fmt.Errorf("invalid group definition: %q", line)
+				return atField(4, fmt.Errorf("invalid witness options: %w", err))
 			}
+			w.Key = f_note.NewVerifierWithValidity(w.Key, validity)
+		}
+		components[name] = w
+		pol.witnesses[name] = witnessDef{file: file, line: lineNum, vkey: vkey, w: w}
+		pol.names = append(pol.names, name)
+	case "group":
+		if len(fields) < 3 {
+			return atField(len(fields)-1, errors.New("group definition must have a name and threshold"))
+		}
 
-			name, N, childrenNames := fields[1], fields[2], fields[3:]
-			if isBadName(name) {
-				return Group{}, fmt.Errorf("invalid group name %q", name)
-			}
-			if _, ok := components[name]; ok {
-				return Group{}, fmt.Errorf("duplicate component name: %q", name)
+		name, N, childrenNames := fields[1], fields[2], slices.Clone(fields[3:])
+		if err := define(name); err != nil {
+			return err
+		}
+		var n int
+		var weights []int
+		switch {
+		case N == "any":
+			n = 1
+		case N == "all":
+			n = len(childrenNames)
+		case strings.HasPrefix(N, weightPrefix):
+			i, err := strconv.ParseUint(strings.TrimPrefix(N, weightPrefix), 10, 16)
+			if err != nil {
+				return atField(2, fmt.Errorf("invalid weighted threshold %q for group %q: %w", N, name, err))
 			}
-			var n int
-			switch N {
-			case "any":
-				n = 1
-			case "all":
-				n = len(childrenNames)
-			default:
-				i, err := strconv.ParseUint(N, 10, 8)
-				if err != nil {
-					return Group{}, fmt.Errorf("invalid threshold %q for group %q: %w", N, name, err)
+			n = int(i)
+			weights = make([]int, len(childrenNames))
+			for i, c := range childrenNames {
+				if childrenNames[i], weights[i], err = parseWeightedChild(c); err != nil {
+					return atField(3+i, fmt.Errorf("invalid member %q of group %q: %w", c, name, err))
 				}
-				n = int(i)
 			}
-			if c := len(childrenNames); n > c {
-				return Group{}, fmt.Errorf("group with %d children cannot have threshold %d", c, n)
+		default:
+			i, err := strconv.ParseUint(N, 10, 8)
+			if err != nil {
+				return atField(2, fmt.Errorf("invalid threshold %q for group %q: %w", N, name, err))
 			}
+			n = int(i)
+		}
+		if total := totalWeight(len(childrenNames), weights); n > total {
+			if weights != nil {
+				return atField(2, fmt.Errorf("group with total weight %d cannot have threshold %d", total, n))
+			}
+			return atField(2, fmt.Errorf("group with %d children cannot have threshold %d", total, n))
+		}
 
-			children := make([]policyComponent, len(childrenNames))
-			for i, cName := range childrenNames {
-				if isBadName(cName) {
-					return Group{}, fmt.Errorf("invalid component name %q", cName)
-				}
-				child, ok := components[cName]
-				if !ok {
-					return Group{}, fmt.Errorf("unknown component %q in group definition", cName)
-				}
-				children[i] = child
+		children := make([]policyComponent, len(childrenNames))
+		resolved := true
+		for i, cName := range childrenNames {
+			if isBadName(cName) {
+				return atField(3+i, fmt.Errorf("invalid component name %q", cName))
 			}
-			wg := NewGroup(n, children...)
-			components[name] = wg
-		case "quorum":
-			if len(fields) != 2 {
-				return Group{}, fmt.Errorf("invalid quorum definition: %q", line)
+			child, ok := components[cName]
+			switch {
+			case ok:
+				children[i] = child
+			case pp.failed[cName]:
+				// The problem with the child has already been reported.
+				resolved = false
+			default:
+				pErr := &ParseError{File: file, Line: lineNum, Column: cols[3+i]}
+				pp.errs = append(pp.errs, pErr)
+				pp.unresolved = append(pp.unresolved, unresolvedRef{name: cName, err: pErr})
+				resolved = false
 			}
-			quorumName = fields[1]
-		default:
-			return Group{}, fmt.Errorf("unknown keyword: %q", fields[0])
 		}
+		if !resolved {
+			return errReported
+		}
+		wg := NewWeightedGroup(n, weights, children...)
+		components[name] = wg
+		pol.groups[name] = groupDef{file: file, line: lineNum, n: n, weights: weights, children: childrenNames}
+		pol.names = append(pol.names, name)
+	case "include":
+		if len(fields) != 2 {
+			return atField(min(len(fields)-1, 2), errors.New("include must name exactly one file"))
+		}
+		if err := pp.include(file, fields[1]); err != nil {
+			return atField(1, err)
+		}
+	case "quorum":
+		if len(fields) != 2 {
+			return atField(min(len(fields)-1, 2), errors.New("quorum must name exactly one component"))
+		}
+		pol.quorum, pol.quorumFile, pol.quorumLine, pol.quorumColumn = fields[1], file, lineNum, cols[1]
+	default:
+		return fmt.Errorf("unknown keyword: %q", fields[0])
 	}
-	if err := scanner.Err(); err != nil {
-		return Group{}, err
+	return nil
+}
+
+// include parses the policy file named by an include line in the given file.
+func (pp *policyParser) include(from, name string) error {
+	if pp.fsys == nil {
+		return errors.New("include is only supported when parsing policy files")
+	}
+	name = path.Join(path.Dir(from), name)
+	if i := slices.Index(pp.including, name); i >= 0 {
+		return fmt.Errorf("include cycle: %s", strings.Join(append(pp.including[i:], name), " -> "))
+	}
+	if pp.parsed[name] {
+		return nil
+	}
+	p, err := fs.ReadFile(pp.fsys, name)
+	if err != nil {
+		return fmt.Errorf("failed to read included file: %w", err)
+	}
+	pp.including = append(pp.including, name)
+	defer func() { pp.including = pp.including[:len(pp.including)-1] }()
+	return pp.parseFile(name, p)
+}
+
+// finish resolves the references to components and the quorum of the parsed
+// policy, and returns the policy or the problems found with it.
+func (pp *policyParser) finish() (*policy, error) {
+	for _, ref := range pp.unresolved {
+		if at, ok := pp.definedAt[ref.name]; ok {
+			ref.err.Err = fmt.Errorf("%w: %q is used before its definition at %s", ErrForwardReference, ref.name, at)
+		} else {
+			ref.err.Err = fmt.Errorf("%w %q in group definition", ErrUnknownComponent, ref.name)
+		}
 	}
 
-	switch quorumName {
+	pol := pp.pol
+	quorumErr := func(err error) {
+		pp.errs = append(pp.errs, &ParseError{File: pol.quorumFile, Line: pol.quorumLine, Column: pol.quorumColumn, Err: err})
+	}
+	switch pol.quorum {
 	case "":
-		return Group{}, fmt.Errorf("policy file must define a quorum")
+		if len(pp.errs) == 0 {
+			return nil, ErrNoQuorum
+		}
 	case "none":
-		return NewGroup(0), nil
 	default:
-		if isBadName(quorumName) {
-			return Group{}, fmt.Errorf("invalid quorum name %q", quorumName)
+		_, ok := pol.components[pol.quorum]
+		switch {
+		case isBadName(pol.quorum):
+			quorumErr(fmt.Errorf("invalid quorum name %q", pol.quorum))
+		case !ok && !pp.failed[pol.quorum]:
+			quorumErr(fmt.Errorf("quorum component %q not found: %w", pol.quorum, ErrUnknownComponent))
 		}
-		policy, ok := components[quorumName]
+	}
+	if len(pp.errs) > 0 {
+		return nil, pp.errs
+	}
+	return pol, nil
+}
+
+// quorumGroup returns the group which must be satisfied for the policy to be
+// satisfied.
+func (p *policy) quorumGroup() Group {
+	if p.quorum == "none" {
+		return NewGroup(0)
+	}
+	policy := p.components[p.quorum]
+	wg, ok := policy.(Group)
+	if !ok {
+		// A single witness can be a policy. Wrap it in a group.
+		return NewGroup(1, policy)
+	}
+	return wg
+}
+
+// weightPrefix introduces the threshold of a weighted group.
+const weightPrefix = "weight>="
+
+// parseWeightedChild parses a member of a weighted group, of the form
+// <name>:<weight>, or <name> for a member with weight 1.
+func parseWeightedChild(c string) (string, int, error) {
+	i := strings.LastIndex(c, ":")
+	if i < 0 {
+		return c, 1, nil
+	}
+	w, err := strconv.ParseUint(c[i+1:], 10, 8)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid weight: %w", err)
+	}
+	if w == 0 {
+		return "", 0, errors.New("weight must be at least 1")
+	}
+	return c[:i], int(w), nil
+}
+
+// parseValidity parses witness key validity options, each of the form
+// <option>=<value>. The supported options are:
+//   - min-size and max-size: inclusive bounds on the checkpoint tree size
+//   - not-before and not-after: inclusive bounds, in RFC 3339 format, on the
+//     cosignature timestamp
+func parseValidity(opts []string) (f_note.Validity, error) {
+	var v f_note.Validity
+	seen := make(map[string]bool)
+	for i, o := range opts {
+		k, val, ok := strings.Cut(o, "=")
 		if !ok {
-			return Group{}, fmt.Errorf("quorum component %q not found", quorumName)
+			return f_note.Validity{}, atField(i, fmt.Errorf("invalid option %q", o))
 		}
-		wg, ok := policy.(Group)
-		if !ok {
-			// A single witness can be a policy. Wrap it in a group.
-			return NewGroup(1, policy), nil
+		if seen[k] {
+			return f_note.Validity{}, atField(i, fmt.Errorf("duplicate option %q", k))
+		}
+		seen[k] = true
+		var err error
+		switch k {
+		case "min-size":
+			v.MinTreeSize, err = strconv.ParseUint(val, 10, 64)
+		case "max-size":
+			v.MaxTreeSize, err = strconv.ParseUint(val, 10, 64)
+		case "not-before":
+			v.NotBefore, err = time.Parse(time.RFC3339, val)
+		case "not-after":
+			v.NotAfter, err = time.Parse(time.RFC3339, val)
+		default:
+			return f_note.Validity{}, atField(i, fmt.Errorf("unknown option %q", k))
+		}
+		if err != nil {
+			return f_note.Validity{}, atField(i, fmt.Errorf("invalid value for option %q: %w", k, err))
 		}
-		return wg, nil
 	}
+	if v.MaxTreeSize > 0 && v.MinTreeSize > v.MaxTreeSize {
+		return f_note.Validity{}, fmt.Errorf("min-size %d is larger than max-size %d", v.MinTreeSize, v.MaxTreeSize)
+	}
+	if !v.NotBefore.IsZero() && !v.NotAfter.IsZero() && v.NotAfter.Before(v.NotBefore) {
+		return f_note.Validity{}, errors.New("not-after is before not-before")
+	}
+	return v, nil
 }
 
 var keywords = map[string]struct{}{
@@ -174,6 +665,7 @@ var keywords = map[string]struct{}{
 	"none":    {},
 	"quorum":  {},
 	"log":     {},
+	"include": {},
 }
 
 func isBadName(n string) bool {
@@ -231,13 +723,46 @@ func (w Witness) Endpoints() map[string]note.Verifier {
 // The threshold should only be set to less than the number of sub-components if these are
 // considered fungible.
 func NewGroup(n int, children ...policyComponent) Group {
-	if n < 0 || n > len(children) {
-		panic(fmt.Errorf("threshold of %d outside bounds for children %s", n, children))
+	return NewWeightedGroup(n, nil, children...)
+}
+
+// NewWeightedGroup creates a grouping of Witness or WitnessGroup in which each
+// sub-component carries the corresponding weight, and which is satisfied when
+// the total weight of the satisfied sub-components is at least n.
+//
+// If weights is nil, every sub-component has weight 1, as with NewGroup.
+func NewWeightedGroup(n int, weights []int, children ...policyComponent) Group {
+	if weights != nil {
+		if len(weights) != len(children) {
+			panic(fmt.Errorf("%d weights given for %d children", len(weights), len(children)))
+		}
+		for _, w := range weights {
+			if w < 1 {
+				panic(fmt.Errorf("invalid weight %d", w))
+			}
+		}
+	}
+	if total := totalWeight(len(children), weights); n < 0 || n > total {
+		panic(fmt.Errorf("threshold of %d outside bounds for children %s with total weight %d", n, children, total))
 	}
 	return Group{
 		Components: children,
 		N:          n,
+		Weights:    weights,
+	}
+}
+
+// totalWeight returns the sum of the weights of n components, each of which
+// has weight 1 if weights is nil.
+func totalWeight(n int, weights []int) int {
+	if weights == nil {
+		return n
+	}
+	total := 0
+	for _, w := range weights {
+		total += w
 	}
+	return total
 }
 
 // Group defines a group of witnesses, and a threshold of
@@ -247,9 +772,21 @@ func NewGroup(n int, children ...policyComponent) Group {
 // represent a threshold of the quorum. For some users this will be a
 // simple majority, but other strategies are available.
 // N must be <= len(WitnessKeys).
+//
+// If Weights is set, it holds the weight of each of the Components, and N is
+// instead a threshold on the total weight of the satisfied Components.
 type Group struct {
 	Components []policyComponent
 	N          int
+	Weights    []int
+}
+
+// weight returns the weight of the i-th component.
+func (wg Group) weight(i int) int {
+	if wg.Weights == nil {
+		return 1
+	}
+	return wg.Weights[i]
 }
 
 // Satisfied returns true if the checkpoint provided has sufficient signatures
@@ -267,9 +804,9 @@ func (wg Group) Satisfied(cp []byte) bool {
 		return true
 	}
 	satisfaction := 0
-	for _, c := range wg.Components {
+	for i, c := range wg.Components {
 		if c.Satisfied(cp) {
-			satisfaction++
+			satisfaction += wg.weight(i)
 		}
 		if satisfaction >= wg.N {
 			return true
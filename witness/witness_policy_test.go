@@ -15,8 +15,15 @@
 package witness
 
 import (
+	"errors"
+	"io/fs"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
+
+	"golang.org/x/mod/sumdb/note"
 )
 
 func TestParsePolicy(t *testing.T) {
@@ -160,6 +167,51 @@ func TestParsePolicy_Errors(t *testing.T) {
 			policy: `group none 1 witness`,
 			errStr: "invalid group name",
 		},
+		{
+			desc:   "witness option without value",
+			policy: "witness w1 sigsum.org+e4ade967+AZuUY6B08pW3QVHu8uvsrxWPcAv9nykap2Nb4oxCee+r https://sigsum.org/witness/ min-size",
+			errStr: "invalid option",
+		},
+		{
+			desc:   "unknown witness option",
+			policy: "witness w1 sigsum.org+e4ade967+AZuUY6B08pW3QVHu8uvsrxWPcAv9nykap2Nb4oxCee+r https://sigsum.org/witness/ colour=blue",
+			errStr: "unknown option",
+		},
+		{
+			desc:   "invalid witness option time",
+			policy: "witness w1 sigsum.org+e4ade967+AZuUY6B08pW3QVHu8uvsrxWPcAv9nykap2Nb4oxCee+r https://sigsum.org/witness/ not-after=yesterday",
+			errStr: "invalid value for option",
+		},
+		{
+			desc:   "weighted threshold too large",
+			policy: "witness w1 " + wit1_vkey + " https://w1.example.com/\nwitness w2 " + wit2_vkey + " https://w2.example.com/\ngroup g1 weight>=4 w1:2 w2\nquorum g1\n",
+			errStr: "group with total weight 3 cannot have threshold 4",
+		},
+		{
+			desc:   "invalid weighted threshold",
+			policy: "witness w1 " + wit1_vkey + " https://w1.example.com/\ngroup g1 weight>=x w1\nquorum g1\n",
+			errStr: "invalid weighted threshold",
+		},
+		{
+			desc:   "zero weight",
+			policy: "witness w1 " + wit1_vkey + " https://w1.example.com/\ngroup g1 weight>=1 w1:0\nquorum g1\n",
+			errStr: "weight must be at least 1",
+		},
+		{
+			desc:   "invalid weight",
+			policy: "witness w1 " + wit1_vkey + " https://w1.example.com/\ngroup g1 weight>=1 w1:heavy\nquorum g1\n",
+			errStr: "invalid weight",
+		},
+		{
+			desc:   "weight in unweighted group",
+			policy: "witness w1 " + wit1_vkey + " https://w1.example.com/\ngroup g1 any w1:1\nquorum g1\n",
+			errStr: "unknown component \"w1:1\"",
+		},
+		{
+			desc:   "empty witness size range",
+			policy: "witness w1 sigsum.org+e4ade967+AZuUY6B08pW3QVHu8uvsrxWPcAv9nykap2Nb4oxCee+r https://sigsum.org/witness/ min-size=10 max-size=5",
+			errStr: "larger than max-size",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -174,3 +226,299 @@ func TestParsePolicy_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePolicy_Weighted(t *testing.T) {
+	policy := `
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+group g1 weight>=3 w1:2 w2 w3:1
+quorum g1
+`
+	g, err := ParsePolicy([]byte(policy))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	if g.N != 3 {
+		t.Errorf("got threshold %d, want 3", g.N)
+	}
+	if want := []int{2, 1, 1}; !slices.Equal(g.Weights, want) {
+		t.Errorf("got weights %v, want %v", g.Weights, want)
+	}
+	if got := len(g.Endpoints()); got != 3 {
+		t.Errorf("got %d endpoints, want 3", got)
+	}
+
+	for _, test := range []struct {
+		desc    string
+		signers []note.Signer
+		want    bool
+	}{
+		{desc: "heavy and light", signers: []note.Signer{wit1Sign, wit3Sign}, want: true},
+		{desc: "all light", signers: []note.Signer{wit2Sign, wit3Sign}, want: false},
+		{desc: "heavy only", signers: []note.Signer{wit1Sign}, want: false},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			cp, err := note.Sign(&note.Note{Text: "example.com/log\n42\nqINS1GRFhWHwdkUeqLEoP4yEMkTBBzxBkGwGQlVlVcs=\n"}, test.signers...)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if got := g.Satisfied(cp); got != test.want {
+				t.Errorf("Satisfied = %t, want %t", got, test.want)
+			}
+		})
+	}
+
+	// Plain groups are unaffected by weights.
+	plain, err := ParsePolicy([]byte(strings.Replace(policy, "weight>=3 w1:2 w2 w3:1", "2 w1 w2 w3", 1)))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	if plain.Weights != nil {
+		t.Errorf("got weights %v for plain group, want nil", plain.Weights)
+	}
+}
+
+func TestParsePolicyFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"common/witnesses": {Data: []byte(`
+witness w1 ` + wit1_vkey + ` https://w1.example.com/
+witness w2 ` + wit2_vkey + ` https://w2.example.com/
+witness w3 ` + wit3_vkey + ` https://w3.example.com/
+`)},
+		"common/groups": {Data: []byte(`
+include witnesses
+group public 2 w1 w2 w3
+quorum public
+`)},
+		"prod": {Data: []byte(`
+include common/groups
+# Already included by common/groups, so this has no effect.
+include common/witnesses
+group prod all public w3
+quorum prod
+`)},
+		"staging": {Data: []byte("include common/groups\n")},
+	}
+	cp12, err := note.Sign(&note.Note{Text: "example.com/log\n42\nqINS1GRFhWHwdkUeqLEoP4yEMkTBBzxBkGwGQlVlVcs=\n"}, wit1Sign, wit2Sign)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want bool
+	}{
+		// prod requires w3 in addition to the public quorum.
+		{name: "prod", want: false},
+		// staging uses the quorum defined by the included file.
+		{name: "staging", want: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g, err := ParsePolicyFS(fsys, test.name)
+			if err != nil {
+				t.Fatalf("ParsePolicyFS: %v", err)
+			}
+			if got := g.Satisfied(cp12); got != test.want {
+				t.Errorf("Satisfied = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicyFS_Errors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"witnesses": {Data: []byte("witness w1 " + wit1_vkey + " https://w1.example.com/\n")},
+		"cycle/a":   {Data: []byte("include ../witnesses\ninclude b\nquorum w1\n")},
+		"cycle/b":   {Data: []byte("\ninclude a\n")},
+		"missing":   {Data: []byte("include nothing\nquorum w1\n")},
+		"escape":    {Data: []byte("include ../witnesses\nquorum w1\n")},
+		"bad/frag":  {Data: []byte("# comment\ngroup g1 any w2\n")},
+		"bad/root":  {Data: []byte("include ../witnesses\ninclude frag\nquorum g1\n")},
+		"quorum":    {Data: []byte("include witnesses\n\nquorum g1\n")},
+	}
+	for _, test := range []struct {
+		name     string
+		wantFile string
+		wantLine int
+		wantErr  string
+	}{
+		{name: "cycle/a", wantFile: "cycle/b", wantLine: 2, wantErr: "include cycle: cycle/a -> cycle/b -> cycle/a"},
+		{name: "missing", wantFile: "missing", wantLine: 1, wantErr: "failed to read included file"},
+		{name: "escape", wantFile: "escape", wantLine: 1, wantErr: "failed to read included file"},
+		{name: "bad/root", wantFile: "bad/frag", wantLine: 2, wantErr: "bad/frag:2:14: unknown component \"w2\""},
+		{name: "quorum", wantFile: "quorum", wantLine: 3, wantErr: "quorum:3:8: quorum component \"g1\" not found"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParsePolicyFS(fsys, test.name)
+			var pErr *ParseError
+			if !errors.As(err, &pErr) {
+				t.Fatalf("ParsePolicyFS() = %v, want *ParseError", err)
+			}
+			if pErr.File != test.wantFile || pErr.Line != test.wantLine {
+				t.Errorf("got error at %s:%d, want %s:%d", pErr.File, pErr.Line, test.wantFile, test.wantLine)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("error %q does not contain %q", err, test.wantErr)
+			}
+		})
+	}
+
+	if _, err := ParsePolicyFS(fsys, "nothing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ParsePolicyFS(missing file) = %v, want %v", err, fs.ErrNotExist)
+	}
+	if _, err := ParsePolicy([]byte("include witnesses\nquorum w1\n")); err == nil || !strings.Contains(err.Error(), "only supported when parsing policy files") {
+		t.Errorf("ParsePolicy() with include = %v, want unsupported error", err)
+	}
+}
+
+func TestParsePolicy_KeyValidity(t *testing.T) {
+	cp, err := note.Sign(&note.Note{Text: "example.com/log\n42\nqINS1GRFhWHwdkUeqLEoP4yEMkTBBzxBkGwGQlVlVcs=\n"}, wit1Sign)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	now := time.Now()
+	for _, test := range []struct {
+		desc string
+		opts string
+		want bool
+	}{
+		{desc: "no options", want: true},
+		{desc: "current key", opts: "not-before=" + now.Add(-time.Hour).Format(time.RFC3339), want: true},
+		{desc: "retired key", opts: "not-after=" + now.Add(-time.Hour).Format(time.RFC3339), want: false},
+		{desc: "size in range", opts: "min-size=10 max-size=42", want: true},
+		{desc: "size out of range", opts: "max-size=41", want: false},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			p, err := ParsePolicy([]byte("witness w1 " + wit1_vkey + " https://example.com/ " + test.opts + "\nquorum w1\n"))
+			if err != nil {
+				t.Fatalf("ParsePolicy: %v", err)
+			}
+			if got := p.Satisfied(cp); got != test.want {
+				t.Errorf("Satisfied = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicy_ErrorTypes(t *testing.T) {
+	for _, test := range []struct {
+		desc      string
+		policy    string
+		wantLine  int
+		wantErrIs error
+	}{
+		{
+			desc:      "no quorum",
+			policy:    "witness w1 " + wit1_vkey + " https://example.com/\n",
+			wantErrIs: ErrNoQuorum,
+		},
+		{
+			desc:      "unknown group member",
+			policy:    "# comment\n\nwitness w1 " + wit1_vkey + " https://example.com/\ngroup g1 any w1 w2\nquorum g1\n",
+			wantLine:  4,
+			wantErrIs: ErrUnknownComponent,
+		},
+		{
+			desc:      "unknown quorum component",
+			policy:    "witness w1 " + wit1_vkey + " https://example.com/\nquorum g1\n",
+			wantLine:  2,
+			wantErrIs: ErrUnknownComponent,
+		},
+		{
+			desc:      "duplicate component",
+			policy:    "witness w1 " + wit1_vkey + " https://example.com/\ngroup w1 any w1\nquorum w1\n",
+			wantLine:  2,
+			wantErrIs: ErrDuplicateComponent,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(test.policy))
+			if !errors.Is(err, test.wantErrIs) {
+				t.Fatalf("got error %v, want %v", err, test.wantErrIs)
+			}
+			var pErr *ParseError
+			if gotLine := errors.As(err, &pErr); gotLine != (test.wantLine > 0) {
+				t.Fatalf("got error %v, want *ParseError: %t", err, test.wantLine > 0)
+			}
+			if pErr != nil && pErr.Line != test.wantLine {
+				t.Errorf("got error on line %d, want %d", pErr.Line, test.wantLine)
+			}
+		})
+	}
+}
+
+func TestParsePolicy_AllErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc   string
+		policy string
+		want   []string
+	}{
+		{
+			desc: "several errors",
+			policy: "witness w1 " + wit1_vkey + " https://w1.example.com/\n" +
+				"witness w2 bad-vkey https://w2.example.com/\n" +
+				"group g1 lots w1\n" +
+				"  bananas\n" +
+				"group g2 any w1 w3 # w3 is not defined\n" +
+				"quorum g2 g1\n",
+			want: []string{
+				`line 2, column 12: invalid witness vkey "bad-vkey"`,
+				`line 3, column 10: invalid threshold "lots" for group "g1"`,
+				`line 4, column 3: unknown keyword: "bananas"`,
+				`line 5, column 17: unknown component "w3" in group definition`,
+				`line 6, column 11: quorum must name exactly one component`,
+			},
+		}, {
+			desc: "forward reference",
+			policy: "group g1 any w1\n" +
+				"witness w1 " + wit1_vkey + " https://w1.example.com/\n" +
+				"quorum g1\n",
+			want: []string{
+				`line 1, column 14: forward reference: "w1" is used before its definition at line 2`,
+			},
+		}, {
+			desc: "no errors from references to invalid components",
+			policy: "witness w1 bad-vkey https://w1.example.com/\n" +
+				"group g1 any w1\n" +
+				"group g2 all g1 w1\n" +
+				"quorum g2\n",
+			want: []string{
+				`line 1, column 12: invalid witness vkey "bad-vkey"`,
+			},
+		}, {
+			desc: "invalid witness option",
+			policy: "witness w1 " + wit1_vkey + " https://w1.example.com/ min-size=1 colour=blue\n" +
+				"quorum w1\n",
+			want: []string{
+				`line 1, column 106: invalid witness options: unknown option "colour"`,
+			},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(test.policy))
+			var errs ParseErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ParsePolicy() = %v, want ParseErrors", err)
+			}
+			if len(errs) != len(test.want) {
+				t.Fatalf("got %d errors, want %d:\n%v", len(errs), len(test.want), err)
+			}
+			for i, want := range test.want {
+				if got := errs[i].Error(); !strings.HasPrefix(got, want) {
+					t.Errorf("error %d = %q, want prefix %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePolicy_ForwardReference(t *testing.T) {
+	_, err := ParsePolicy([]byte("group g1 any w1\nwitness w1 " + wit1_vkey + " https://w1.example.com/\nquorum g1\n"))
+	if !errors.Is(err, ErrForwardReference) {
+		t.Errorf("ParsePolicy() = %v, want %v", err, ErrForwardReference)
+	}
+	if errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("ParsePolicy() = %v, should not be %v", err, ErrUnknownComponent)
+	}
+}
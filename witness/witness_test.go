@@ -132,6 +132,24 @@ func TestGroup_Satisfied(t *testing.T) {
 			signers:         []note.Signer{wit2Sign, wit3Sign},
 			expectSatisfied: false,
 		},
+		{
+			desc:            "Weighted, heavy witness alone",
+			group:           NewWeightedGroup(2, []int{2, 1, 1}, wit1, wit2, wit3),
+			signers:         []note.Signer{wit1Sign},
+			expectSatisfied: true,
+		},
+		{
+			desc:            "Weighted, light witnesses together",
+			group:           NewWeightedGroup(2, []int{2, 1, 1}, wit1, wit2, wit3),
+			signers:         []note.Signer{wit2Sign, wit3Sign},
+			expectSatisfied: true,
+		},
+		{
+			desc:            "Weighted, light witness alone",
+			group:           NewWeightedGroup(2, []int{2, 1, 1}, wit1, wit2, wit3),
+			signers:         []note.Signer{wit3Sign},
+			expectSatisfied: false,
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {